@@ -67,7 +67,7 @@ func (c *Client) GetClientKeys() ([]ClientKey, error) {
 		SetResult(&result)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, nil)
+	c.setStandardHeaders(req, "GetClientKeys", nil)
 
 	// Override specific headers
 	req.SetHeader("Authorization", "Basic "+auth)
@@ -98,7 +98,7 @@ func (c *Client) GetClientCredentialsToken() (*TokenResponse, error) {
 		SetResult(&token)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, nil)
+	c.setStandardHeaders(req, "GetClientCredentialsToken", nil)
 
 	// Override Content-Type for form data
 	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
@@ -174,10 +174,19 @@ func (c *Client) createSessionFromTokenResponse(mfpUserID string, tokenResp *Tok
 		return nil, fmt.Errorf("no domain user ID found in account links")
 	}
 
+	if c.tokenStore != nil {
+		if err := c.tokenStore.Save(session); err != nil {
+			// The tokens are already issued, so hand them back rather than leave them unrevoked
+			return session, &TokenStoreError{Err: err}
+		}
+	}
+
 	return session, nil
 }
 
-// Login authenticates with username and password and returns the user's tokens
+// Login authenticates with username and password and returns the user's tokens.
+// If saving the session to the client's token store fails, Login returns the session
+// together with a *TokenStoreError.
 func (c *Client) Login(username, password string) (*UserSession, error) {
 	// Create the JWT claims
 	claims := jwt.MapClaims{
@@ -204,15 +213,14 @@ func (c *Client) Login(username, password string) (*UserSession, error) {
 	data.Set("scope", "openid")
 
 	// Create a client that doesn't follow redirects
-	noRedirectClient := resty.New()
-	noRedirectClient.SetBaseURL(identityBaseURL)
+	noRedirectClient := c.newRestyClient(c.identityURL)
 	noRedirectClient.SetRedirectPolicy(resty.NoRedirectPolicy())
 
 	req := noRedirectClient.R().
 		SetBody(data.Encode())
 
 	// Set standard headers first
-	c.setStandardHeaders(req, nil)
+	c.setStandardHeaders(req, "Login", nil)
 
 	// Override specific headers
 	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
@@ -253,7 +261,9 @@ func (c *Client) Login(username, password string) (*UserSession, error) {
 	return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode())
 }
 
-// RefreshUserToken refreshes a user's access token using their refresh token
+// RefreshUserToken refreshes a user's access token using their refresh token.
+// If saving the session to the client's token store fails, RefreshUserToken returns the
+// session together with a *TokenStoreError.
 func (c *Client) RefreshUserToken(mfpUserID string, refreshToken string) (*UserSession, error) {
 	if refreshToken == "" {
 		return nil, fmt.Errorf("no refresh token provided")
@@ -272,7 +282,7 @@ func (c *Client) RefreshUserToken(mfpUserID string, refreshToken string) (*UserS
 		SetResult(&tokenResp)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, nil)
+	c.setStandardHeaders(req, "RefreshUserToken", nil)
 
 	// Override Content-Type for form data
 	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
@@ -312,7 +322,7 @@ func (c *Client) ExchangeCodeForToken(code string) (*TokenResponse, error) {
 		SetResult(&tokenResp)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, nil)
+	c.setStandardHeaders(req, "ExchangeCodeForToken", nil)
 
 	// Override Content-Type for form data
 	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
@@ -0,0 +1,61 @@
+package cassette_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/cassette"
+	"github.com/seonixx/myfitnesspal/mfptest"
+)
+
+func TestRecordAndReplayLogin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.json")
+
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	rec, err := cassette.New(path, cassette.Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := srv.NewClient(myfitnesspal.WithTransport(rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, interaction := range rec.Interactions() {
+		for _, secret := range []string{"password", recorded.AccessToken, recorded.RefreshToken, "test@example.com"} {
+			if strings.Contains(interaction.Request.Body+interaction.Response.Body, `"`+secret+`"`) {
+				t.Errorf("%s %s: cassette contains %q", interaction.Request.Method, interaction.Request.Path, secret)
+			}
+		}
+	}
+
+	// Replay against the same URLs, with the server gone
+	srv.Close()
+	replay, err := cassette.New(path, cassette.Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err = srv.NewClient(myfitnesspal.WithTransport(replay))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatalf("replaying Login: %v", err)
+	}
+	if session.UserID != recorded.UserID || session.DomainUserID != recorded.DomainUserID {
+		t.Errorf("replayed session is for %s/%s, want %s/%s", session.UserID, session.DomainUserID, recorded.UserID, recorded.DomainUserID)
+	}
+}
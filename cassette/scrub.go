@@ -0,0 +1,79 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/seonixx/myfitnesspal/internal/redact"
+)
+
+// scrubQuery normalizes a query string and removes secrets from it
+func scrubQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redact.String(rawQuery)
+	}
+	redact.Values(values)
+	return NormalizeQuery(values.Encode())
+}
+
+// scrubBody removes secrets from a request or response body
+func scrubBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			redact.Values(values)
+			return values.Encode()
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var v interface{}
+		if err := decoder.Decode(&v); err == nil {
+			if data, err := json.Marshal(scrubJSON("", v)); err == nil {
+				return string(data)
+			}
+		}
+	}
+
+	return redact.String(string(body))
+}
+
+// scrubJSON removes secrets from a decoded JSON value
+func scrubJSON(key string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			value[k] = scrubJSON(k, child)
+		}
+		return value
+	case []interface{}:
+		for i, child := range value {
+			value[i] = scrubJSON(key, child)
+		}
+		return value
+	case string:
+		switch {
+		case key == "k":
+			// Signing keys must still decode as base64 for the client to start
+			return base64.RawURLEncoding.EncodeToString([]byte(redact.Placeholder))
+		case redact.IsJWT(value):
+			// Tokens such as id_token keep their subject so a replayed login can find the user
+			return redact.JWT(value)
+		case redact.Sensitive(key):
+			return redact.Placeholder
+		}
+		return redact.String(value)
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal/internal/redact"
+)
+
+func TestScrubBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		secrets     []string
+	}{
+		{"form", "application/x-www-form-urlencoded", "grant_type=password&username=jane%40example.org&password=hunter2", []string{"jane", "hunter2"}},
+		{"json", "application/json", `{"access_token":"abc","id_token":"def","user":{"email":"jane@example.org"}}`, []string{"abc", "def", "jane"}},
+		{"truncated json", "application/json", `{"refresh_token":"abc","id_token":"def"`, []string{"abc", "def"}},
+		{"text", "text/plain", "contact jane@example.org", []string{"jane"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scrubBody(tt.contentType, []byte(tt.body))
+			for _, secret := range tt.secrets {
+				if strings.Contains(got, secret) {
+					t.Errorf("scrubBody(%q) = %q, still contains %q", tt.body, got, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestScrubBodyKeepsSigningKeysDecodable(t *testing.T) {
+	got := scrubBody("application/json", []byte(`{"keys":[{"kid":"1","k":"c2VjcmV0"}]}`))
+	want := base64.RawURLEncoding.EncodeToString([]byte(redact.Placeholder))
+	if !strings.Contains(got, `"k":"`+want+`"`) {
+		t.Errorf("scrubBody = %s, want the key replaced with %s", got, want)
+	}
+}
+
+func TestScrubQueryNormalizes(t *testing.T) {
+	if got, want := scrubQuery("q=apple&access_token=abc"), "access_token=REDACTED&q=apple"; got != want {
+		t.Errorf("scrubQuery = %q, want %q", got, want)
+	}
+}
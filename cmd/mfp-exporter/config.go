@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+// defaultSessionDir is where sessions are kept if the config doesn't say, relative to the config file
+const defaultSessionDir = "sessions"
+
+// Config is the exporter's configuration file
+type Config struct {
+	SessionDir string       `json:"session_dir"` // FileTokenStore directory, relative to the config file
+	Users      []UserConfig `json:"users"`
+}
+
+// UserConfig is a user to export metrics for
+type UserConfig struct {
+	Name   string `json:"name"`    // Value of the user label
+	UserID string `json:"user_id"` // Identity user ID the user's session is saved under
+}
+
+// loadConfig reads and validates a config file
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing config: %w", err)
+	}
+
+	if len(config.Users) == 0 {
+		return nil, fmt.Errorf("no users configured")
+	}
+	names := map[string]bool{}
+	for i, user := range config.Users {
+		if user.Name == "" {
+			return nil, fmt.Errorf("user %d has no name", i+1)
+		}
+		if names[user.Name] {
+			return nil, fmt.Errorf("duplicate user name: %s", user.Name)
+		}
+		names[user.Name] = true
+
+		if user.UserID == "" {
+			return nil, fmt.Errorf("user %s has no user ID", user.Name)
+		}
+	}
+
+	if config.SessionDir == "" {
+		config.SessionDir = defaultSessionDir
+	}
+	if !filepath.IsAbs(config.SessionDir) {
+		config.SessionDir = filepath.Join(filepath.Dir(path), config.SessionDir)
+	}
+
+	return &config, nil
+}
+
+// loadSessionKey decodes MFP_SESSION_KEY, returning nil if it isn't set
+func loadSessionKey() ([]byte, error) {
+	encoded := os.Getenv("MFP_SESSION_KEY")
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding MFP_SESSION_KEY: %w", err)
+	}
+	return key, nil
+}
+
+// migrateSessions encrypts the configured users' plain text sessions with key. Sessions that are
+// already encrypted are left alone. The store rejects plain text sessions once a key is set, so
+// this must be run deliberately, after checking the session files are the ones you saved.
+func migrateSessions(logger *slog.Logger, config *Config, key []byte) error {
+	if key == nil {
+		return fmt.Errorf("MFP_SESSION_KEY must be set to migrate sessions")
+	}
+	store, err := myfitnesspal.NewFileTokenStore(config.SessionDir, key)
+	if err != nil {
+		return err
+	}
+	plain, err := myfitnesspal.NewFileTokenStore(config.SessionDir, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range config.Users {
+		_, err := store.Load(user.UserID)
+		if err == nil {
+			logger.Info("session already encrypted", "user", user.Name)
+			continue
+		}
+		if !errors.Is(err, myfitnesspal.ErrSessionNotEncrypted) {
+			return fmt.Errorf("error loading session for user %s: %w", user.Name, err)
+		}
+
+		session, err := plain.Load(user.UserID)
+		if err != nil {
+			return fmt.Errorf("error loading session for user %s: %w", user.Name, err)
+		}
+		if err := store.Save(session); err != nil {
+			return fmt.Errorf("error encrypting session for user %s: %w", user.Name, err)
+		}
+		logger.Info("encrypted session", "user", user.Name)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+func TestLoadConfigResolvesSessionDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"users": [{"name": "alice", "user_id": "12345"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, defaultSessionDir); config.SessionDir != want {
+		t.Errorf("SessionDir = %q, want %q", config.SessionDir, want)
+	}
+}
+
+func TestMigrateSessions(t *testing.T) {
+	config := &Config{SessionDir: t.TempDir(), Users: []UserConfig{{Name: "alice", UserID: "12345"}}}
+	key := make([]byte, 32)
+	session := &myfitnesspal.UserSession{UserID: "12345", AccessToken: "access", RefreshToken: "refresh"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	plain, err := myfitnesspal.NewFileTokenStore(config.SessionDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.Save(session); err != nil {
+		t.Fatal(err)
+	}
+
+	// With a key, plain text sessions are rejected until they're migrated
+	store, err := myfitnesspal.NewFileTokenStore(config.SessionDir, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(session.UserID); !errors.Is(err, myfitnesspal.ErrSessionNotEncrypted) {
+		t.Fatalf("Load before migrating = %v, want ErrSessionNotEncrypted", err)
+	}
+
+	if err := migrateSessions(logger, config, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(session.UserID); err != nil {
+		t.Errorf("Load after migrating: %v", err)
+	}
+	if _, err := plain.Load(session.UserID); !errors.Is(err, myfitnesspal.ErrSessionKey) {
+		t.Errorf("plain text Load after migrating = %v, want ErrSessionKey", err)
+	}
+
+	// Running it again leaves encrypted sessions alone
+	if err := migrateSessions(logger, config, key); err != nil {
+		t.Errorf("second migration: %v", err)
+	}
+	if err := migrateSessions(logger, config, nil); err == nil {
+		t.Error("migrating without a key succeeded")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+// minBackoff is the wait after the first failed refresh. It doubles with each further failure.
+const minBackoff = 30 * time.Second
+
+// refreshMargin is how long before a session expires its tokens are refreshed
+const refreshMargin = time.Minute
+
+// userExporter keeps one user's metrics up to date
+type userExporter struct {
+	user    UserConfig
+	client  *myfitnesspal.Client
+	metrics *metrics
+	logger  *slog.Logger
+	store   *myfitnesspal.FileTokenStore
+	session *myfitnesspal.UserSession
+}
+
+// run refreshes the user's metrics every interval until ctx is done, backing off on errors
+func (u *userExporter) run(ctx context.Context, interval, maxBackoff time.Duration) {
+	var backoff time.Duration
+	for {
+		wait := interval
+		if err := u.refresh(); err != nil {
+			backoff = min(max(backoff*2, minBackoff), maxBackoff)
+			wait = backoff
+			u.metrics.up.WithLabelValues(u.user.Name).Set(0)
+			u.metrics.refreshErrors.WithLabelValues(u.user.Name).Inc()
+			u.logger.Warn("refresh failed", "user", u.user.Name, "error", err, "retry_in", wait)
+		} else {
+			backoff = 0
+			u.metrics.up.WithLabelValues(u.user.Name).Set(1)
+			u.metrics.lastSuccess.WithLabelValues(u.user.Name).SetToCurrentTime()
+			u.logger.Debug("refreshed", "user", u.user.Name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// refresh fetches today's diary, goals, water and latest weight and updates the gauges
+func (u *userExporter) refresh() error {
+	session, err := u.currentSession()
+	if err != nil {
+		return err
+	}
+
+	today, err := u.client.Today(session)
+	if err != nil {
+		return fmt.Errorf("error getting today's date: %w", err)
+	}
+
+	summary, err := u.client.GetDailySummary(session, today)
+	if err != nil {
+		return fmt.Errorf("error getting daily summary: %w", err)
+	}
+
+	water, err := u.client.GetWater(session, today)
+	if err != nil {
+		return fmt.Errorf("error getting water: %w", err)
+	}
+
+	weight, err := u.client.GetLatestMeasurement(session, myfitnesspal.Weight)
+	if err != nil {
+		return fmt.Errorf("error getting weight: %w", err)
+	}
+
+	u.metrics.setSummary(u.user.Name, summary)
+	u.metrics.water.WithLabelValues(u.user.Name).Set(water.Milliliters)
+	if weight != nil {
+		kg, err := weight.Kilograms()
+		if err != nil {
+			return fmt.Errorf("error converting weight: %w", err)
+		}
+		u.metrics.weight.WithLabelValues(u.user.Name).Set(kg)
+	}
+
+	return nil
+}
+
+// currentSession loads the user's session and refreshes its tokens when they are about to expire.
+// The client saves refreshed sessions to the store, as the old refresh token stops working.
+func (u *userExporter) currentSession() (*myfitnesspal.UserSession, error) {
+	if u.session == nil {
+		session, err := u.store.Load(u.user.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading session: %w", err)
+		}
+		if session.RefreshToken == "" {
+			return nil, fmt.Errorf("session has no refresh token")
+		}
+		u.session = session
+	}
+
+	if time.Until(u.session.ExpiresAt) > refreshMargin {
+		return u.session, nil
+	}
+
+	session, err := u.client.RefreshUserToken(u.session.UserID, u.session.RefreshToken)
+	var storeErr *myfitnesspal.TokenStoreError
+	if errors.As(err, &storeErr) {
+		// The new tokens work, but only until a restart. Keep them and save again next refresh.
+		u.logger.Warn("error saving refreshed session", "user", u.user.Name, "error", err)
+		err = nil
+	}
+	if err != nil {
+		// Reload next time in case the saved session was replaced with a fresh login
+		u.session = nil
+		return nil, fmt.Errorf("error refreshing session: %w", err)
+	}
+	u.session = session
+	return session, nil
+}
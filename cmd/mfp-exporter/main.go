@@ -0,0 +1,146 @@
+// Command mfp-exporter exposes MyFitnessPal diary, goal and measurement metrics for Prometheus.
+//
+// It reads MFP_CLIENT_ID and MFP_CLIENT_SECRET from the environment or a .env file, and a JSON
+// config listing the users to export by identity user ID, and the directory their sessions are in:
+//
+//	{"session_dir": "sessions", "users": [{"name": "alice", "user_id": "12345"}]}
+//
+// The directory is a myfitnesspal.FileTokenStore, such as one a client given WithTokenStore logged
+// the users in with. If MFP_SESSION_KEY is set to a base64 encoded AES key sessions are encrypted
+// with it, and plain text sessions are rejected; run once with -migrate-plaintext-sessions to
+// encrypt sessions saved before the key was set. The client saves sessions back to the store
+// whenever the exporter refreshes their tokens.
+//
+// Usage:
+//
+//	mfp-exporter -config config.json -listen :9723 -interval 5m
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/seonixx/myfitnesspal"
+)
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the config file")
+	listen := flag.String("listen", ":9723", "address to serve /metrics on")
+	interval := flag.Duration("interval", 5*time.Minute, "how often to refresh metrics")
+	maxBackoff := flag.Duration("max-backoff", time.Hour, "longest wait between retries after errors")
+	debug := flag.Bool("debug", false, "log every request")
+	migrate := flag.Bool("migrate-plaintext-sessions", false, "encrypt plain text sessions with MFP_SESSION_KEY and exit")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	var err error
+	if *migrate {
+		err = runMigration(logger, *configPath)
+	} else {
+		err = run(logger, *configPath, *listen, *interval, *maxBackoff)
+	}
+	if err != nil {
+		logger.Error("exiting", "error", err)
+		os.Exit(1)
+	}
+}
+
+// runMigration encrypts the configured users' plain text sessions
+func runMigration(logger *slog.Logger, configPath string) error {
+	_ = godotenv.Load()
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	key, err := loadSessionKey()
+	if err != nil {
+		return err
+	}
+	return migrateSessions(logger, config, key)
+}
+
+// run starts the exporters and serves metrics until interrupted
+func run(logger *slog.Logger, configPath, listen string, interval, maxBackoff time.Duration) error {
+	// The environment may also be set directly, so a missing .env file is fine
+	_ = godotenv.Load()
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	sessionKey, err := loadSessionKey()
+	if err != nil {
+		return err
+	}
+
+	store, err := myfitnesspal.NewFileTokenStore(config.SessionDir, sessionKey)
+	if err != nil {
+		return err
+	}
+
+	client, err := myfitnesspal.NewClient(
+		os.Getenv("MFP_CLIENT_ID"),
+		os.Getenv("MFP_CLIENT_SECRET"),
+		myfitnesspal.WithLogger(logger),
+		myfitnesspal.WithTokenStore(store),
+	)
+	if err != nil {
+		return err
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	m := newMetrics(registry)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, user := range config.Users {
+		exporter := &userExporter{user: user, client: client, metrics: m, logger: logger, store: store}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exporter.run(ctx, interval, maxBackoff)
+		}()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("serving metrics", "address", listen, "users", len(config.Users))
+	err = server.ListenAndServe()
+	stop()
+	wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
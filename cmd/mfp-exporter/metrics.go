@@ -0,0 +1,108 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/seonixx/myfitnesspal"
+)
+
+// metrics are the exported gauges, labelled by configured user name
+type metrics struct {
+	caloriesGoal      *prometheus.GaugeVec
+	caloriesFood      *prometheus.GaugeVec
+	caloriesExercise  *prometheus.GaugeVec
+	caloriesRemaining *prometheus.GaugeVec
+	macroGoal         *prometheus.GaugeVec
+	macroConsumed     *prometheus.GaugeVec
+	macroRemaining    *prometheus.GaugeVec
+	water             *prometheus.GaugeVec
+	weight            *prometheus.GaugeVec
+	up                *prometheus.GaugeVec
+	lastSuccess       *prometheus.GaugeVec
+	refreshErrors     *prometheus.CounterVec
+}
+
+// newMetrics creates the metrics and registers them
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	user := []string{"user"}
+	macro := []string{"user", "nutrient"}
+
+	m := &metrics{
+		caloriesGoal: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_calories_goal",
+			Help: "Today's calorie goal.",
+		}, user),
+		caloriesFood: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_calories_food",
+			Help: "Calories logged as food today.",
+		}, user),
+		caloriesExercise: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_calories_exercise",
+			Help: "Calories burned through exercise and activity tracker adjustments today.",
+		}, user),
+		caloriesRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_calories_remaining",
+			Help: "Calories remaining today: goal minus food plus exercise. Negative when over goal.",
+		}, user),
+		macroGoal: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_macro_goal_grams",
+			Help: "Today's macronutrient goal in grams.",
+		}, macro),
+		macroConsumed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_macro_consumed_grams",
+			Help: "Macronutrients logged today in grams.",
+		}, macro),
+		macroRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_macro_remaining_grams",
+			Help: "Macronutrients remaining today in grams. Negative when over goal.",
+		}, macro),
+		water: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_water_milliliters",
+			Help: "Water logged today in milliliters.",
+		}, user),
+		weight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_weight_kilograms",
+			Help: "Most recently logged weight in kilograms.",
+		}, user),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_up",
+			Help: "Whether the last refresh of the user's metrics succeeded.",
+		}, user),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mfp_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful refresh of the user's metrics.",
+		}, user),
+		refreshErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mfp_refresh_errors_total",
+			Help: "Failed refreshes of the user's metrics.",
+		}, user),
+	}
+
+	registerer.MustRegister(
+		m.caloriesGoal, m.caloriesFood, m.caloriesExercise, m.caloriesRemaining,
+		m.macroGoal, m.macroConsumed, m.macroRemaining,
+		m.water, m.weight, m.up, m.lastSuccess, m.refreshErrors,
+	)
+	return m
+}
+
+// setSummary sets the calorie and macro gauges from a daily summary
+func (m *metrics) setSummary(user string, summary *myfitnesspal.DailySummary) {
+	m.caloriesGoal.WithLabelValues(user).Set(summary.GoalCalories)
+	m.caloriesFood.WithLabelValues(user).Set(summary.FoodCalories)
+	m.caloriesExercise.WithLabelValues(user).Set(summary.ExerciseCalories)
+	m.caloriesRemaining.WithLabelValues(user).Set(summary.RemainingCalories)
+
+	macros := []struct {
+		name                      string
+		goal, consumed, remaining float64
+	}{
+		{"carbohydrates", summary.Goal.Carbohydrates, summary.Consumed.Carbohydrates, summary.Remaining.Carbohydrates},
+		{"protein", summary.Goal.Protein, summary.Consumed.Protein, summary.Remaining.Protein},
+		{"fat", summary.Goal.Fat, summary.Consumed.Fat, summary.Remaining.Fat},
+	}
+	for _, macro := range macros {
+		m.macroGoal.WithLabelValues(user, macro.name).Set(macro.goal)
+		m.macroConsumed.WithLabelValues(user, macro.name).Set(macro.consumed)
+		m.macroRemaining.WithLabelValues(user, macro.name).Set(macro.remaining)
+	}
+}
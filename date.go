@@ -0,0 +1,237 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// dateLayout is the format the API uses for diary dates
+const dateLayout = "2006-01-02"
+
+// Date represents a calendar day in the user's diary, independent of timezone
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate creates a Date, normalizing out of range values the same way time.Date does
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the Date on which t falls in t's location
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// Today returns the current date in the given location
+func Today(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// ParseDate parses a date in YYYY-MM-DD format
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date in YYYY-MM-DD format
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether the date is unset
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// Time returns midnight at the start of the date in the given location
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Weekday returns the day of the week of the date
+func (d Date) Weekday() time.Weekday {
+	return d.Time(time.UTC).Weekday()
+}
+
+// AddDays returns the date n days after d. n can be negative.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+// DaysSince returns the number of days from other to d
+func (d Date) DaysSince(other Date) int {
+	return int((d.Time(time.UTC).Unix() - other.Time(time.UTC).Unix()) / (24 * 60 * 60))
+}
+
+// Before reports whether d is before other
+func (d Date) Before(other Date) bool {
+	return d.DaysSince(other) < 0
+}
+
+// After reports whether d is after other
+func (d Date) After(other Date) bool {
+	return d.DaysSince(other) > 0
+}
+
+// MarshalText encodes the date in YYYY-MM-DD format
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a date in YYYY-MM-DD format
+func (d *Date) UnmarshalText(text []byte) error {
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes the date as a YYYY-MM-DD string, or null if the date is unset
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a YYYY-MM-DD string. Empty strings and null leave the date unset.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid date: %s", data)
+	}
+	if s == nil || *s == "" {
+		*d = Date{}
+		return nil
+	}
+	return d.UnmarshalText([]byte(*s))
+}
+
+// DateRange returns every date from start to end inclusive, in order
+func DateRange(start, end Date) iter.Seq[Date] {
+	return func(yield func(Date) bool) {
+		for d := start; !d.After(end); d = d.AddDays(1) {
+			if !yield(d) {
+				return
+			}
+		}
+	}
+}
+
+// countryTimeZones maps countries that use a single timezone to that timezone
+var countryTimeZones = map[string]string{
+	"AT": "Europe/Vienna",
+	"BE": "Europe/Brussels",
+	"CH": "Europe/Zurich",
+	"DE": "Europe/Berlin",
+	"DK": "Europe/Copenhagen",
+	"ES": "Europe/Madrid",
+	"FI": "Europe/Helsinki",
+	"FR": "Europe/Paris",
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"IN": "Asia/Kolkata",
+	"IT": "Europe/Rome",
+	"JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul",
+	"NL": "Europe/Amsterdam",
+	"NO": "Europe/Oslo",
+	"NZ": "Pacific/Auckland",
+	"PH": "Asia/Manila",
+	"PL": "Europe/Warsaw",
+	"PT": "Europe/Lisbon",
+	"SE": "Europe/Stockholm",
+	"SG": "Asia/Singapore",
+	"ZA": "Africa/Johannesburg",
+}
+
+// ErrUnknownTimeZone is returned when a user's timezone can't be worked out from their profile,
+// because their country is unknown or spans several timezones
+var ErrUnknownTimeZone = errors.New("unknown time zone")
+
+// TimeZone guesses the user's timezone from their profile location and locale.
+// Countries spanning several timezones (such as the US, Canada and Australia), and unknown
+// countries, return an error wrapping ErrUnknownTimeZone.
+func (p *UserProfile) TimeZone() (*time.Location, error) {
+	country := p.Location.Country
+	if country == "" {
+		country = localeCountry(p.Locale)
+	}
+
+	name, ok := countryTimeZones[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("%w for country %q", ErrUnknownTimeZone, country)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("error loading time zone %s: %w", name, err)
+	}
+	return loc, nil
+}
+
+// GetTimeZone fetches the user's timezone from their diary preferences,
+// falling back to a guess based on their profile locale. It returns an error wrapping
+// ErrUnknownTimeZone if neither gives a timezone.
+func (c *Client) GetTimeZone(session *UserSession) (*time.Location, error) {
+	var result struct {
+		Item struct {
+			LocationPreferences struct {
+				TimeZone string `json:"time_zone"`
+			} `json:"location_preferences"`
+		} `json:"item"`
+	}
+
+	req := c.apiClient.R().
+		SetQueryParam("fields[]", "location_preferences")
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "GetTimeZone", session)
+
+	resp, err := req.Get("/v2/users/" + session.DomainUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time zone: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get time zone failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse time zone response: %w", err)
+	}
+
+	if name := result.Item.LocationPreferences.TimeZone; name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, nil
+		}
+	}
+
+	user, err := c.GetUser(session)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user info: %w", err)
+	}
+	return user.Profile.TimeZone()
+}
+
+// Today returns the current date in the user's timezone
+func (c *Client) Today(session *UserSession) (Date, error) {
+	loc, err := c.GetTimeZone(session)
+	if err != nil {
+		return Date{}, err
+	}
+	return Today(loc), nil
+}
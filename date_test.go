@@ -0,0 +1,36 @@
+package myfitnesspal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUserProfileTimeZone(t *testing.T) {
+	tests := []struct {
+		country string
+		locale  string
+		want    string
+	}{
+		{"GB", "", "Europe/London"},
+		{"", "de_DE", "Europe/Berlin"},
+		{"jp", "en_US", "Asia/Tokyo"},
+	}
+	for _, tt := range tests {
+		p := UserProfile{Locale: tt.locale, Location: ProfileLocation{Country: tt.country}}
+		loc, err := p.TimeZone()
+		if err != nil {
+			t.Errorf("TimeZone() for %q/%q: %v", tt.country, tt.locale, err)
+			continue
+		}
+		if loc.String() != tt.want {
+			t.Errorf("TimeZone() for %q/%q = %s, want %s", tt.country, tt.locale, loc, tt.want)
+		}
+	}
+
+	for _, country := range []string{"US", "AU", ""} {
+		p := UserProfile{Location: ProfileLocation{Country: country}}
+		if loc, err := p.TimeZone(); !errors.Is(err, ErrUnknownTimeZone) {
+			t.Errorf("TimeZone() for %q = %v, %v, want ErrUnknownTimeZone", country, loc, err)
+		}
+	}
+}
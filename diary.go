@@ -0,0 +1,233 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FoodEntry represents a food entry in the user's diary
+type FoodEntry struct {
+	ID                  string              `json:"id"`
+	Type                string              `json:"type"`
+	ClientID            string              `json:"client_id"`
+	Date                Date                `json:"date"`
+	MealName            string              `json:"meal_name"`
+	MealPosition        MealNumber          `json:"meal_position"`
+	Food                FoodItem            `json:"food"`
+	ServingSize         ServingSize         `json:"serving_size"`
+	Servings            float64             `json:"servings"`
+	MealFoodID          string              `json:"meal_food_id"`
+	NutritionalContents NutritionalContents `json:"nutritional_contents"`
+	Geolocation         Geolocation         `json:"geolocation"`
+	ImageIDs            []string            `json:"image_ids"`
+	Tags                []string            `json:"tags"`
+	ConsumedAt          *string             `json:"consumed_at"`
+	LoggedAt            *string             `json:"logged_at"`
+	LoggedAtOffset      *string             `json:"logged_at_offset"`
+}
+
+// localTimestampLayouts are the formats the API uses for entry timestamps without an offset
+var localTimestampLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ConsumedTime returns when the food was eaten, if it was recorded
+func (e *FoodEntry) ConsumedTime() (time.Time, bool) {
+	return parseEntryTimestamp(e.ConsumedAt, e.LoggedAtOffset)
+}
+
+// LoggedTime returns when the entry was added to the diary, if it was recorded
+func (e *FoodEntry) LoggedTime() (time.Time, bool) {
+	return parseEntryTimestamp(e.LoggedAt, e.LoggedAtOffset)
+}
+
+// parseEntryTimestamp parses a diary entry timestamp. Timestamps without their own
+// offset are interpreted using the entry's logged_at_offset, or UTC if there is none.
+func parseEntryTimestamp(value, offset *string) (time.Time, bool) {
+	if value == nil || *value == "" {
+		return time.Time{}, false
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, *value); err == nil {
+		return t, true
+	}
+
+	loc := time.UTC
+	if offset != nil {
+		if l, ok := parseUTCOffset(*offset); ok {
+			loc = l
+		}
+	}
+
+	for _, layout := range localTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, *value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseUTCOffset parses an offset in either "+hh:mm" form or as a number of seconds
+func parseUTCOffset(offset string) (*time.Location, bool) {
+	offset = strings.TrimSpace(offset)
+	if t, err := time.Parse("-07:00", offset); err == nil {
+		_, seconds := t.Zone()
+		return time.FixedZone(offset, seconds), true
+	}
+	if seconds, err := strconv.Atoi(offset); err == nil {
+		return time.FixedZone("", seconds), true
+	}
+	return nil, false
+}
+
+// getDiaryItems fetches the raw diary items of the given types for a date
+func (c *Client) getDiaryItems(session *UserSession, operation string, date Date, types ...string) ([]json.RawMessage, error) {
+	req := c.apiClient.R().
+		SetQueryParam("entry_date", date.String()).
+		SetQueryParam("types", strings.Join(types, ","))
+
+	// Set standard headers first
+	c.setStandardHeaders(req, operation, session)
+
+	resp, err := req.Get("/v2/diary")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diary: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get diary failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Items []json.RawMessage `json:"items"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse diary response: %w", err)
+	}
+
+	return result.Items, nil
+}
+
+// GetFoodDiary fetches the food entries in the user's diary for a date
+func (c *Client) GetFoodDiary(session *UserSession, date Date) ([]FoodEntry, error) {
+	items, err := c.getDiaryItems(session, "GetFoodDiary", date, "food_entry")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]FoodEntry, 0, len(items))
+	for _, item := range items {
+		var entry FoodEntry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse food entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// DeleteDiaryEntry deletes a food or exercise entry from the user's diary
+func (c *Client) DeleteDiaryEntry(session *UserSession, entryID string) error {
+	if entryID == "" {
+		return fmt.Errorf("no diary entry ID provided")
+	}
+
+	req := c.apiClient.R()
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "DeleteDiaryEntry", session)
+
+	resp, err := req.Delete("/v2/diary/" + entryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete diary entry: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusNoContent {
+		return fmt.Errorf("delete diary entry failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return nil
+}
+
+// GetFoodDiaryRange fetches the food entries in the user's diary from start to end inclusive
+func (c *Client) GetFoodDiaryRange(session *UserSession, start, end Date) ([]FoodEntry, error) {
+	var entries []FoodEntry
+	for date := range DateRange(start, end) {
+		dayEntries, err := c.GetFoodDiary(session, date)
+		if err != nil {
+			return nil, fmt.Errorf("error getting diary for %s: %w", date, err)
+		}
+		entries = append(entries, dayEntries...)
+	}
+	return entries, nil
+}
+
+// EatingWindow represents the span of time in which a user ate on a given day
+type EatingWindow struct {
+	Date      Date
+	FirstMeal time.Time
+	LastMeal  time.Time
+	Entries   int // Number of entries with a recorded consumption time
+}
+
+// Duration returns the time between the first and last meal of the day
+func (w EatingWindow) Duration() time.Duration {
+	return w.LastMeal.Sub(w.FirstMeal)
+}
+
+// EatingWindows groups entries by date and returns the eating window of each day, in date order.
+// Entries without a recorded consumption time are ignored.
+func EatingWindows(entries []FoodEntry) []EatingWindow {
+	windows := map[Date]*EatingWindow{}
+	for i := range entries {
+		consumed, ok := entries[i].ConsumedTime()
+		if !ok {
+			continue
+		}
+
+		w, ok := windows[entries[i].Date]
+		if !ok {
+			w = &EatingWindow{Date: entries[i].Date, FirstMeal: consumed, LastMeal: consumed}
+			windows[entries[i].Date] = w
+		}
+		if consumed.Before(w.FirstMeal) {
+			w.FirstMeal = consumed
+		}
+		if consumed.After(w.LastMeal) {
+			w.LastMeal = consumed
+		}
+		w.Entries++
+	}
+
+	result := make([]EatingWindow, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result
+}
+
+// GetEatingWindow fetches the user's diary for a date and returns their eating window.
+// It returns nil if no entries on that date have a recorded consumption time.
+func (c *Client) GetEatingWindow(session *UserSession, date Date) (*EatingWindow, error) {
+	entries, err := c.GetFoodDiary(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := EatingWindows(entries)
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	return &windows[0], nil
+}
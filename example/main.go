@@ -56,8 +56,11 @@ func main() {
 	fmt.Printf("  Display Name: %s\n", user.Profile.DisplayName)
 	fmt.Printf("  Gender: %s\n", user.Profile.Gender)
 	fmt.Printf("  Birthdate: %s\n", user.Profile.Birthdate)
-	fmt.Printf("  Weight: %.1f kg\n", user.Profile.Weight*0.453592) // Convert from pounds to kg
-	fmt.Printf("  Height: %.1f cm\n", user.Profile.HeightInCM())
+	fmt.Printf("  Weight: %s\n", user.Profile.FormatWeight())
+	fmt.Printf("  Height: %s\n", user.Profile.FormatHeight())
+	if age, err := user.Profile.Age(); err == nil {
+		fmt.Printf("  Age: %d\n", age)
+	}
 	fmt.Printf("  Location: %s, %s\n", user.Profile.Location.PostalCode, user.Profile.Location.Country)
 	fmt.Printf("Emails:\n")
 	for _, email := range user.ProfileEmails.Emails {
@@ -106,10 +109,15 @@ func main() {
 	fmt.Printf("\nCreated food item! ID: %s, Description: %s, Brand: %s\n",
 		foodResp.Items[0].ID, foodResp.Items[0].Description, foodResp.Items[0].BrandName)
 
-	// Add the created food to the diary
+	// Add the created food to today's diary
+	today, err := client.Today(session)
+	if err != nil {
+		log.Fatalf("Error getting today's date: %v", err)
+	}
+
 	addReq := myfitnesspal.FoodDiaryAddRequest{
 		Type:         "food_entry",
-		Date:         "2025-05-26",
+		Date:         today,
 		MealPosition: 2,
 		Food: struct {
 			ID      string `json:"id"`
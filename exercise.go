@@ -0,0 +1,209 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ExerciseType is the kind of exercise, which determines what can be logged for it
+type ExerciseType string
+
+const (
+	Cardio   ExerciseType = "cardio"
+	Strength ExerciseType = "strength"
+)
+
+// Exercise represents an exercise from the MyFitnessPal database
+type Exercise struct {
+	ID                string       `json:"id"`
+	Version           string       `json:"version"`
+	Description       string       `json:"description"`
+	Type              ExerciseType `json:"type"`
+	MET               float64      `json:"mets,omitempty"` // Metabolic equivalent, used to estimate calories burned
+	CaloriesPerMinute float64      `json:"calories_per_minute,omitempty"`
+	Public            bool         `json:"public"`
+	UserID            string       `json:"user_id,omitempty"`
+	Deleted           bool         `json:"deleted,omitempty"`
+}
+
+// ExerciseItem represents a custom exercise to be created
+type ExerciseItem struct {
+	UserID            string       `json:"user_id"`
+	Description       string       `json:"description"`
+	Type              ExerciseType `json:"type"`
+	CaloriesPerMinute float64      `json:"calories_per_minute,omitempty"` // Only used for cardio
+	Public            bool         `json:"public"`
+}
+
+// CreateExerciseResponse represents the response from creating a custom exercise
+type CreateExerciseResponse struct {
+	Items []Exercise `json:"items"`
+}
+
+// ExerciseDiaryAddRequest represents the request to add an exercise entry to the diary.
+// Cardio entries need a Duration, strength entries need Sets and RepsPerSet.
+type ExerciseDiaryAddRequest struct {
+	Type     string `json:"type"` // always "exercise_entry"
+	Date     Date   `json:"date"`
+	Exercise struct {
+		ID      string `json:"id"`
+		Version string `json:"version"`
+	} `json:"exercise"`
+	Duration     int        `json:"duration,omitempty"` // Seconds
+	Energy       *Energy    `json:"energy,omitempty"`   // Calories burned. Estimated by MyFitnessPal if not set.
+	Sets         int        `json:"sets,omitempty"`
+	RepsPerSet   int        `json:"reps_per_set,omitempty"`
+	WeightPerSet float64    `json:"weight_per_set,omitempty"` // Pounds
+	StartTime    *time.Time `json:"start_time,omitempty"`
+}
+
+// ExerciseEntry represents an exercise entry in the user's diary
+type ExerciseEntry struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	ClientID     string   `json:"client_id"`
+	Date         Date     `json:"date"`
+	Exercise     Exercise `json:"exercise"`
+	Duration     int      `json:"duration"` // Seconds
+	Energy       Energy   `json:"energy"`
+	Sets         int      `json:"sets"`
+	RepsPerSet   int      `json:"reps_per_set"`
+	WeightPerSet float64  `json:"weight_per_set"` // Pounds
+	StartTime    *string  `json:"start_time"`
+}
+
+// DurationTime returns the duration of the exercise
+func (e *ExerciseEntry) DurationTime() time.Duration {
+	return time.Duration(e.Duration) * time.Second
+}
+
+// ExerciseDiaryAddResponse represents the response from adding an exercise entry
+type ExerciseDiaryAddResponse struct {
+	Items []ExerciseEntry `json:"items"`
+}
+
+// SearchExercise searches for exercises in the MyFitnessPal database
+func (c *Client) SearchExercise(session *UserSession, query string, maxItems int) ([]Exercise, error) {
+	if maxItems <= 0 {
+		maxItems = 25
+	}
+
+	req := c.apiClient.R().
+		SetQueryParams(map[string]string{
+			"q":         query,
+			"max_items": strconv.Itoa(maxItems),
+		})
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "SearchExercise", session)
+
+	resp, err := req.Get("/v2/search/exercises")
+	if err != nil {
+		return nil, fmt.Errorf("failed to make exercise search request: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("exercise search request failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Items []struct {
+			Item Exercise `json:"item"`
+		} `json:"items"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse exercise search response: %w", err)
+	}
+
+	exercises := make([]Exercise, 0, len(result.Items))
+	for _, item := range result.Items {
+		exercises = append(exercises, item.Item)
+	}
+	return exercises, nil
+}
+
+// CreateExercise creates a new custom exercise for the user
+func (c *Client) CreateExercise(session *UserSession, exercise ExerciseItem) (*CreateExerciseResponse, error) {
+	if exercise.Type != Cardio && exercise.Type != Strength {
+		return nil, fmt.Errorf("invalid exercise type: %s. Must be 'cardio' or 'strength'", exercise.Type)
+	}
+
+	var response CreateExerciseResponse
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"item": exercise,
+		}).
+		SetResult(&response)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "CreateExercise", session)
+
+	resp, err := req.Post("/v2/exercises")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exercise: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("create exercise request failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return &response, nil
+}
+
+// AddExerciseToDiary adds a cardio or strength entry to the user's diary
+func (c *Client) AddExerciseToDiary(session *UserSession, params ExerciseDiaryAddRequest) (*ExerciseDiaryAddResponse, error) {
+	if params.Date.IsZero() {
+		return nil, fmt.Errorf("no diary date provided")
+	}
+	if params.Duration <= 0 && params.Sets <= 0 {
+		return nil, fmt.Errorf("exercise entry needs a duration or sets")
+	}
+
+	var respData ExerciseDiaryAddResponse
+	// Wrap the request in an items array
+	body := map[string]interface{}{
+		"items": []ExerciseDiaryAddRequest{params},
+	}
+
+	req := c.apiClient.R().
+		SetBody(body)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "AddExerciseToDiary", session)
+
+	resp, err := req.Post("/v2/diary")
+	if err != nil {
+		return nil, fmt.Errorf("failed to add exercise to diary: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("add exercise to diary failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+	if err := json.Unmarshal(resp.Body(), &respData); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &respData, nil
+}
+
+// GetExerciseDiary fetches the exercise entries in the user's diary for a date
+func (c *Client) GetExerciseDiary(session *UserSession, date Date) ([]ExerciseEntry, error) {
+	items, err := c.getDiaryItems(session, "GetExerciseDiary", date, "exercise_entry")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ExerciseEntry, 0, len(items))
+	for _, item := range items {
+		var entry ExerciseEntry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse exercise entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
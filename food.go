@@ -8,8 +8,11 @@ import (
 	"time"
 )
 
+// MealNumber is the position of a meal in the user's diary
 type MealNumber int
 
+// Default meal positions. Premium users can rename these and add up to MaxMeals meals,
+// see GetMealConfig.
 const (
 	Breakfast MealNumber = iota
 	Lunch
@@ -71,6 +74,8 @@ type FoodSearchResult struct {
 
 // FoodItem represents a food item to be created
 type FoodItem struct {
+	ID                  string              `json:"id,omitempty"`      // Set by the API
+	Version             string              `json:"version,omitempty"` // Set by the API
 	UserID              string              `json:"user_id"`
 	BrandName           string              `json:"brand_name"`
 	Description         string              `json:"description"`
@@ -103,12 +108,60 @@ type NutritionalContents struct {
 	VitaminC           float64 `json:"vitamin_c,omitempty"`
 }
 
+// Add returns the sum of two sets of nutritional contents, with energy in calories
+func (n NutritionalContents) Add(other NutritionalContents) NutritionalContents {
+	return n.combine(other, 1)
+}
+
+// Sub returns the difference of two sets of nutritional contents, with energy in calories
+func (n NutritionalContents) Sub(other NutritionalContents) NutritionalContents {
+	return n.combine(other, -1)
+}
+
+// combine adds sign times other to n
+func (n NutritionalContents) combine(other NutritionalContents, sign float64) NutritionalContents {
+	return NutritionalContents{
+		Calcium:            n.Calcium + sign*other.Calcium,
+		Carbohydrates:      n.Carbohydrates + sign*other.Carbohydrates,
+		Cholesterol:        n.Cholesterol + sign*other.Cholesterol,
+		Energy:             Energy{Unit: "calories", Value: n.Energy.Calories() + sign*other.Energy.Calories()},
+		Fat:                n.Fat + sign*other.Fat,
+		Fiber:              n.Fiber + sign*other.Fiber,
+		Grams:              n.Grams + sign*other.Grams,
+		Iron:               n.Iron + sign*other.Iron,
+		MonounsaturatedFat: n.MonounsaturatedFat + sign*other.MonounsaturatedFat,
+		NetCarbs:           n.NetCarbs + sign*other.NetCarbs,
+		PolyunsaturatedFat: n.PolyunsaturatedFat + sign*other.PolyunsaturatedFat,
+		Potassium:          n.Potassium + sign*other.Potassium,
+		Protein:            n.Protein + sign*other.Protein,
+		SaturatedFat:       n.SaturatedFat + sign*other.SaturatedFat,
+		Sodium:             n.Sodium + sign*other.Sodium,
+		Sugar:              n.Sugar + sign*other.Sugar,
+		TransFat:           n.TransFat + sign*other.TransFat,
+		VitaminA:           n.VitaminA + sign*other.VitaminA,
+		VitaminC:           n.VitaminC + sign*other.VitaminC,
+	}
+}
+
+// Scale returns the nutritional contents multiplied by factor, with energy in calories
+func (n NutritionalContents) Scale(factor float64) NutritionalContents {
+	return NutritionalContents{}.combine(n, factor)
+}
+
 // Energy represents the energy content of a food item
 type Energy struct {
 	Unit  string  `json:"unit"`
 	Value float64 `json:"value"`
 }
 
+// Calories returns the energy in calories, converting from kilojoules if needed
+func (e Energy) Calories() float64 {
+	if e.Unit == "kilojoules" {
+		return e.Value / 4.184
+	}
+	return e.Value
+}
+
 // ServingSize represents a serving size for a food item
 type ServingSize struct {
 	Value               float64 `json:"value"`
@@ -137,37 +190,23 @@ type CreateFoodResponse struct {
 // FoodDiaryAddRequest represents the request to add a food entry to the diary
 type FoodDiaryAddRequest struct {
 	Type         string     `json:"type"` // always "food_entry"
-	Date         string     `json:"date"`
-	MealPosition MealNumber `json:"meal_position"` // 0: Breakfast, 1: Lunch, 2: Dinner, 3: Snacks
+	Date         Date       `json:"date"`
+	MealPosition MealNumber `json:"meal_position"` // 0: Breakfast, 1: Lunch, 2: Dinner, 3: Snacks, up to MaxMeals
 	Food         struct {
 		ID      string `json:"id"`
 		Version string `json:"version"`
 	} `json:"food"`
-	Servings    float64     `json:"servings"`
-	ServingSize ServingSize `json:"serving_size"`
+	Servings    float64      `json:"servings"`
+	ServingSize ServingSize  `json:"serving_size"`
+	ConsumedAt  *time.Time   `json:"consumed_at,omitempty"` // When the food was eaten, sent with its timezone offset
+	ImageIDs    []string     `json:"image_ids,omitempty"`   // IDs returned by UploadImage
+	Tags        []string     `json:"tags,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
 }
 
 // FoodDiaryAddResponse represents the response from adding a food entry
 type FoodDiaryAddResponse struct {
-	Items []struct {
-		ID                  string              `json:"id"`
-		Type                string              `json:"type"`
-		ClientID            string              `json:"client_id"`
-		Date                string              `json:"date"`
-		MealName            string              `json:"meal_name"`
-		MealPosition        int                 `json:"meal_position"`
-		Food                FoodItem            `json:"food"`
-		ServingSize         ServingSize         `json:"serving_size"`
-		Servings            float64             `json:"servings"`
-		MealFoodID          string              `json:"meal_food_id"`
-		NutritionalContents NutritionalContents `json:"nutritional_contents"`
-		Geolocation         struct{}            `json:"geolocation"`
-		ImageIDs            []string            `json:"image_ids"`
-		Tags                []string            `json:"tags"`
-		ConsumedAt          *string             `json:"consumed_at"`
-		LoggedAt            *string             `json:"logged_at"`
-		LoggedAtOffset      *string             `json:"logged_at_offset"`
-	} `json:"items"`
+	Items []FoodEntry `json:"items"`
 }
 
 // CreateFood creates a new food item in the MyFitnessPal database
@@ -182,7 +221,7 @@ func (c *Client) CreateFood(session *UserSession, food FoodItem) (*CreateFoodRes
 		SetResult(&response)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, session)
+	c.setStandardHeaders(req, "CreateFood", session)
 
 	resp, err := req.Post("/v2/foods")
 	if err != nil {
@@ -196,7 +235,16 @@ func (c *Client) CreateFood(session *UserSession, food FoodItem) (*CreateFoodRes
 	return &response, nil
 }
 
+// AddFoodToDiary adds a food entry to the user's diary.
+// Use MealConfig.Validate to check MealPosition against the user's own meals.
 func (c *Client) AddFoodToDiary(session *UserSession, params FoodDiaryAddRequest) (*FoodDiaryAddResponse, error) {
+	if params.Date.IsZero() {
+		return nil, fmt.Errorf("no diary date provided")
+	}
+	if !params.MealPosition.Valid() {
+		return nil, fmt.Errorf("invalid meal position: %d", int(params.MealPosition))
+	}
+
 	var respData FoodDiaryAddResponse
 	// Wrap the request in an items array
 	body := map[string]interface{}{
@@ -208,7 +256,7 @@ func (c *Client) AddFoodToDiary(session *UserSession, params FoodDiaryAddRequest
 		SetBody(body)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, session)
+	c.setStandardHeaders(req, "AddFoodToDiary", session)
 
 	resp, err := req.Post("/v2/diary")
 
@@ -238,7 +286,8 @@ func (c *Client) SearchFood(session *UserSession, params SearchFoodRequest) ([]F
 		*params.MaxItems = 25
 	}
 
-	if params.Scope != nil {
+	if params.Scope == nil {
+		params.Scope = new(string)
 		*params.Scope = "all"
 	}
 
@@ -270,7 +319,7 @@ func (c *Client) SearchFood(session *UserSession, params SearchFoodRequest) ([]F
 		SetQueryParam("fields[]", "description")
 
 	// Set standard headers first
-	c.setStandardHeaders(req, session)
+	c.setStandardHeaders(req, "SearchFood", session)
 
 	// Add flow ID header for search
 	req.SetHeader("mfp-flow-id", fmt.Sprintf("%x-%x-%x-%x-%x",
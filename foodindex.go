@@ -0,0 +1,218 @@
+package myfitnesspal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// foodIndexHalfLife is the number of days after which a diary entry counts half as much
+	foodIndexHalfLife = 30.0
+	// otherMealWeight is how much an entry counts towards a meal it was not logged to
+	otherMealWeight = 0.1
+	// sameWeekdayWeight is how much more an entry counts on the weekday it was logged
+	sameWeekdayWeight = 1.5
+)
+
+// FoodIndex ranks the foods in a user's diary history by frequency, recency and meal,
+// independent of the recent and frequent lists MyFitnessPal keeps
+type FoodIndex struct {
+	foods map[string]*indexedFood
+}
+
+// indexedFood is everything the index knows about one food
+type indexedFood struct {
+	food    FoodItem
+	uses    []foodUse
+	entries map[string]bool // Entry IDs already indexed
+}
+
+// foodUse is one time a food was logged
+type foodUse struct {
+	date        Date
+	meal        MealNumber
+	servingSize ServingSize
+	servings    float64
+}
+
+// FoodSuggestion represents a food the user is likely to log, with their typical serving
+type FoodSuggestion struct {
+	Food        FoodItem
+	ServingSize ServingSize
+	Servings    float64
+	Count       int // Times logged in the indexed history
+	LastUsed    Date
+	Score       float64
+}
+
+// DiaryAddRequest returns a request to log the suggested food with the typical serving
+func (s FoodSuggestion) DiaryAddRequest(date Date, meal MealNumber) FoodDiaryAddRequest {
+	req := FoodDiaryAddRequest{
+		Type:         "food_entry",
+		Date:         date,
+		MealPosition: meal,
+		Servings:     s.Servings,
+		ServingSize:  s.ServingSize,
+	}
+	req.Food.ID = s.Food.ID
+	req.Food.Version = s.Food.Version
+	return req
+}
+
+// NewFoodIndex builds an index from diary entries
+func NewFoodIndex(entries []FoodEntry) *FoodIndex {
+	idx := &FoodIndex{foods: map[string]*indexedFood{}}
+	for _, entry := range entries {
+		idx.Add(entry)
+	}
+	return idx
+}
+
+// BuildFoodIndex reads the user's diary from start to end inclusive and indexes it
+func (c *Client) BuildFoodIndex(session *UserSession, start, end Date) (*FoodIndex, error) {
+	entries, err := c.GetFoodDiaryRange(session, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error reading diary history: %w", err)
+	}
+	return NewFoodIndex(entries), nil
+}
+
+// Add indexes a diary entry. Entries without a food ID, or already indexed, are ignored.
+func (idx *FoodIndex) Add(entry FoodEntry) {
+	if entry.Food.ID == "" {
+		return
+	}
+
+	f, ok := idx.foods[entry.Food.ID]
+	if !ok {
+		f = &indexedFood{food: entry.Food, entries: map[string]bool{}}
+		idx.foods[entry.Food.ID] = f
+	}
+	if entry.ID != "" {
+		if f.entries[entry.ID] {
+			return
+		}
+		f.entries[entry.ID] = true
+	}
+
+	// Keep the latest version of the food
+	if len(f.uses) == 0 || !entry.Date.Before(f.lastUsed()) {
+		f.food = entry.Food
+	}
+
+	f.uses = append(f.uses, foodUse{
+		date:        entry.Date,
+		meal:        entry.MealPosition,
+		servingSize: entry.ServingSize,
+		servings:    entry.Servings,
+	})
+}
+
+// Len returns the number of distinct foods in the index
+func (idx *FoodIndex) Len() int {
+	return len(idx.foods)
+}
+
+// Ranked returns every food in the index ranked by how often and how recently it was logged
+func (idx *FoodIndex) Ranked(asOf Date) []FoodSuggestion {
+	return idx.rank(func(use foodUse) float64 {
+		return recencyWeight(asOf, use.date)
+	}, 0)
+}
+
+// Suggest returns the foods the user is most likely to log to a meal on a date,
+// favouring foods logged to that meal and on the same weekday. A limit of 0 returns all foods.
+func (idx *FoodIndex) Suggest(meal MealNumber, date Date, limit int) []FoodSuggestion {
+	weekday := date.Weekday()
+	return idx.rank(func(use foodUse) float64 {
+		weight := recencyWeight(date, use.date)
+		if use.meal != meal {
+			weight *= otherMealWeight
+		}
+		if use.date.Weekday() == weekday {
+			weight *= sameWeekdayWeight
+		}
+		return weight
+	}, limit)
+}
+
+// rank scores each food by summing the weight of its uses and returns them best first
+func (idx *FoodIndex) rank(weight func(foodUse) float64, limit int) []FoodSuggestion {
+	suggestions := make([]FoodSuggestion, 0, len(idx.foods))
+	for _, f := range idx.foods {
+		var score float64
+		for _, use := range f.uses {
+			score += weight(use)
+		}
+
+		servingSize, servings := f.typicalServing()
+		suggestions = append(suggestions, FoodSuggestion{
+			Food:        f.food,
+			ServingSize: servingSize,
+			Servings:    servings,
+			Count:       len(f.uses),
+			LastUsed:    f.lastUsed(),
+			Score:       score,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Food.ID < suggestions[j].Food.ID
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// recencyWeight halves the weight of an entry every foodIndexHalfLife days before asOf
+func recencyWeight(asOf, date Date) float64 {
+	age := float64(asOf.DaysSince(date))
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, age/foodIndexHalfLife)
+}
+
+// lastUsed returns the most recent date the food was logged
+func (f *indexedFood) lastUsed() Date {
+	var last Date
+	for _, use := range f.uses {
+		if use.date.After(last) {
+			last = use.date
+		}
+	}
+	return last
+}
+
+// typicalServing returns the serving size the user logs the food with most often,
+// and the median number of servings they log with it
+func (f *indexedFood) typicalServing() (ServingSize, float64) {
+	counts := map[ServingSize]int{}
+	var best ServingSize
+	for _, use := range f.uses {
+		counts[use.servingSize]++
+		if counts[use.servingSize] > counts[best] {
+			best = use.servingSize
+		}
+	}
+
+	var servings []float64
+	for _, use := range f.uses {
+		if use.servingSize == best {
+			servings = append(servings, use.servings)
+		}
+	}
+	sort.Float64s(servings)
+
+	mid := len(servings) / 2
+	if len(servings)%2 == 0 {
+		return best, (servings[mid-1] + servings[mid]) / 2
+	}
+	return best, servings[mid]
+}
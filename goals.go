@@ -0,0 +1,223 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// NutrientGoal represents a user's daily nutrition targets.
+// Macros can be set in grams or as a percentage of energy, ratios take precedence when set.
+type NutrientGoal struct {
+	DayOfWeek          string  `json:"day_of_week,omitempty"` // e.g. "monday". Empty for the default goal.
+	Energy             Energy  `json:"energy"`
+	Carbohydrates      float64 `json:"carbohydrates,omitempty"`      // Grams
+	Protein            float64 `json:"protein,omitempty"`            // Grams
+	Fat                float64 `json:"fat,omitempty"`                // Grams
+	CarbohydrateRatio  float64 `json:"carbohydrate_ratio,omitempty"` // Percent of energy
+	ProteinRatio       float64 `json:"protein_ratio,omitempty"`      // Percent of energy
+	FatRatio           float64 `json:"fat_ratio,omitempty"`          // Percent of energy
+	SaturatedFat       float64 `json:"saturated_fat,omitempty"`
+	PolyunsaturatedFat float64 `json:"polyunsaturated_fat,omitempty"`
+	MonounsaturatedFat float64 `json:"monounsaturated_fat,omitempty"`
+	TransFat           float64 `json:"trans_fat,omitempty"`
+	Cholesterol        float64 `json:"cholesterol,omitempty"`
+	Sodium             float64 `json:"sodium,omitempty"`
+	Potassium          float64 `json:"potassium,omitempty"`
+	Fiber              float64 `json:"fiber,omitempty"`
+	Sugar              float64 `json:"sugar,omitempty"`
+	VitaminA           float64 `json:"vitamin_a,omitempty"`
+	VitaminC           float64 `json:"vitamin_c,omitempty"`
+	Calcium            float64 `json:"calcium,omitempty"`
+	Iron               float64 `json:"iron,omitempty"`
+}
+
+// MacroGrams returns the goal's carbohydrate, protein and fat targets in grams,
+// converting from percentages of energy where those are set
+func (g NutrientGoal) MacroGrams() (carbohydrates, protein, fat float64) {
+	calories := g.Energy.Calories()
+
+	carbohydrates, protein, fat = g.Carbohydrates, g.Protein, g.Fat
+	if g.CarbohydrateRatio > 0 {
+		carbohydrates = calories * g.CarbohydrateRatio / 100 / 4
+	}
+	if g.ProteinRatio > 0 {
+		protein = calories * g.ProteinRatio / 100 / 4
+	}
+	if g.FatRatio > 0 {
+		fat = calories * g.FatRatio / 100 / 9
+	}
+	return carbohydrates, protein, fat
+}
+
+// Contents returns the goal as nutritional contents, with macros in grams
+func (g NutrientGoal) Contents() NutritionalContents {
+	carbohydrates, protein, fat := g.MacroGrams()
+	return NutritionalContents{
+		Calcium:            g.Calcium,
+		Carbohydrates:      carbohydrates,
+		Cholesterol:        g.Cholesterol,
+		Energy:             Energy{Unit: "calories", Value: g.Energy.Calories()},
+		Fat:                fat,
+		Fiber:              g.Fiber,
+		Iron:               g.Iron,
+		MonounsaturatedFat: g.MonounsaturatedFat,
+		PolyunsaturatedFat: g.PolyunsaturatedFat,
+		Potassium:          g.Potassium,
+		Protein:            protein,
+		SaturatedFat:       g.SaturatedFat,
+		Sodium:             g.Sodium,
+		Sugar:              g.Sugar,
+		TransFat:           g.TransFat,
+		VitaminA:           g.VitaminA,
+		VitaminC:           g.VitaminC,
+	}
+}
+
+// validate checks that macro percentages, if used, add up to 100
+func (g NutrientGoal) validate() error {
+	ratios := g.CarbohydrateRatio + g.ProteinRatio + g.FatRatio
+	if ratios != 0 && math.Abs(ratios-100) > 0.5 {
+		return fmt.Errorf("macro percentages must add up to 100, got %v", ratios)
+	}
+	return nil
+}
+
+// NutritionGoals represents a user's default nutrition goal and any per-weekday overrides
+type NutritionGoals struct {
+	ValidFrom   Date           `json:"valid_from"`
+	DefaultGoal NutrientGoal   `json:"default_goal"`
+	DailyGoals  []NutrientGoal `json:"daily_goals,omitempty"`
+}
+
+// ForDate returns the goal that applies on a date, using the weekday override if there is one
+func (g *NutritionGoals) ForDate(date Date) NutrientGoal {
+	weekday := strings.ToLower(date.Weekday().String())
+	for _, goal := range g.DailyGoals {
+		if strings.EqualFold(goal.DayOfWeek, weekday) {
+			return goal
+		}
+	}
+	return g.DefaultGoal
+}
+
+// GetNutritionGoals fetches the nutrition goals in effect for the user on a date
+func (c *Client) GetNutritionGoals(session *UserSession, date Date) (*NutritionGoals, error) {
+	req := c.apiClient.R().
+		SetQueryParam("date", date.String())
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "GetNutritionGoals", session)
+
+	resp, err := req.Get("/v2/nutrient-goals")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nutrition goals: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get nutrition goals failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Items []NutritionGoals `json:"items"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse nutrition goals response: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return nil, fmt.Errorf("no nutrition goals found for %s", date)
+	}
+
+	return &result.Items[0], nil
+}
+
+// UpdateNutritionGoals replaces the user's nutrition goals from goals.ValidFrom onwards
+func (c *Client) UpdateNutritionGoals(session *UserSession, goals NutritionGoals) (*NutritionGoals, error) {
+	if goals.ValidFrom.IsZero() {
+		return nil, fmt.Errorf("no valid from date provided")
+	}
+	if err := goals.DefaultGoal.validate(); err != nil {
+		return nil, err
+	}
+	for _, goal := range goals.DailyGoals {
+		if goal.DayOfWeek == "" {
+			return nil, fmt.Errorf("daily goal has no day of week")
+		}
+		if err := goal.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s goal: %w", goal.DayOfWeek, err)
+		}
+	}
+
+	var result struct {
+		Item NutritionGoals `json:"item"`
+	}
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"item": goals,
+		}).
+		SetResult(&result)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "UpdateNutritionGoals", session)
+
+	resp, err := req.Post("/v2/nutrient-goals")
+	if err != nil {
+		return nil, fmt.Errorf("failed to update nutrition goals: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("update nutrition goals failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return &result.Item, nil
+}
+
+// GoalProgress compares what a user ate on a day against their goal
+type GoalProgress struct {
+	Date      Date
+	Goal      NutritionalContents
+	Consumed  NutritionalContents
+	Remaining NutritionalContents // Negative values mean the goal was exceeded
+}
+
+// DiaryTotals sums the nutritional contents of diary entries
+func DiaryTotals(entries []FoodEntry) NutritionalContents {
+	totals := NutritionalContents{Energy: Energy{Unit: "calories"}}
+	for _, entry := range entries {
+		totals = totals.Add(entry.NutritionalContents)
+	}
+	return totals
+}
+
+// CompareToGoal compares a day's diary entries against the goal for that day
+func CompareToGoal(date Date, entries []FoodEntry, goal NutrientGoal) GoalProgress {
+	consumed := DiaryTotals(entries)
+	target := goal.Contents()
+	return GoalProgress{
+		Date:      date,
+		Goal:      target,
+		Consumed:  consumed,
+		Remaining: target.Sub(consumed),
+	}
+}
+
+// GetGoalProgress fetches a day's diary and goals and compares them
+func (c *Client) GetGoalProgress(session *UserSession, date Date) (*GoalProgress, error) {
+	goals, err := c.GetNutritionGoals(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.GetFoodDiary(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := CompareToGoal(date, entries, goals.ForDate(date))
+	return &progress, nil
+}
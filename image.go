@@ -0,0 +1,103 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Image represents a meal photo stored by MyFitnessPal
+type Image struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// Geolocation represents where a diary entry was logged
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// IsZero reports whether no location was recorded
+func (g Geolocation) IsZero() bool {
+	return g == Geolocation{}
+}
+
+// imageContentTypes maps supported image file extensions to their content type
+var imageContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".heic": "image/heic",
+	".webp": "image/webp",
+}
+
+// UploadImage uploads a meal photo. Attach the returned image ID to a diary entry
+// with FoodDiaryAddRequest.ImageIDs.
+func (c *Client) UploadImage(session *UserSession, fileName string, image io.Reader) (*Image, error) {
+	contentType, ok := imageContentTypes[strings.ToLower(filepath.Ext(fileName))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported image type: %s", fileName)
+	}
+
+	var result struct {
+		Item Image `json:"item"`
+	}
+
+	req := c.apiClient.R().
+		SetMultipartField("image", filepath.Base(fileName), contentType, image)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "UploadImage", session)
+
+	// The multipart Content-Type is set when the body is written
+	req.Header.Del("Content-Type")
+
+	resp, err := req.Post("/v2/images")
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload image: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("upload image failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse upload image response: %w", err)
+	}
+
+	return &result.Item, nil
+}
+
+// GetImage fetches an image's details, including the URL it can be downloaded from
+func (c *Client) GetImage(session *UserSession, imageID string) (*Image, error) {
+	var result struct {
+		Item Image `json:"item"`
+	}
+
+	req := c.apiClient.R()
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "GetImage", session)
+
+	resp, err := req.Get("/v2/images/" + imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get image failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse image response: %w", err)
+	}
+
+	return &result.Item, nil
+}
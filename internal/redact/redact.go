@@ -0,0 +1,130 @@
+// Package redact holds the rules for removing secrets and personal data from MyFitnessPal
+// traffic. Request logging and cassette recording both use it, so they hide the same things.
+package redact
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces secret values
+const Placeholder = "REDACTED"
+
+// Email replaces email addresses, so redacted data still holds a valid address
+const Email = "user@example.com"
+
+// fields are form, query and JSON fields whose values are always redacted
+var fields = []string{
+	"access_token",
+	"refresh_token",
+	"token",
+	"id_token",
+	"client_secret",
+	"credentials",
+	"password",
+	"username",
+	"code",
+	"data",
+}
+
+// Headers are headers whose values are always redacted
+var Headers = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+var (
+	sensitive    = map[string]bool{}
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	jwtOnly      = regexp.MustCompile(`^` + jwtPattern.String() + `$`)
+	// jsonFields matches sensitive string fields in JSON that couldn't be decoded, such as a truncated body
+	jsonFields = regexp.MustCompile(`("(?:` + strings.Join(fields, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+func init() {
+	for _, field := range fields {
+		sensitive[field] = true
+	}
+}
+
+// Sensitive reports whether a form, query or JSON field always holds a secret
+func Sensitive(field string) bool {
+	return sensitive[field]
+}
+
+// String removes secret JSON fields, JWTs and email addresses from free text
+func String(s string) string {
+	s = jsonFields.ReplaceAllString(s, `$1"`+Placeholder+`"`)
+	s = jwtPattern.ReplaceAllStringFunc(s, JWT)
+	return emailPattern.ReplaceAllString(s, Email)
+}
+
+// IsJWT reports whether s is a whole JWT
+func IsJWT(s string) bool {
+	return jwtOnly.MatchString(s)
+}
+
+// JWT replaces a JWT with an unsigned one carrying only its subject,
+// which the client needs to identify the user when replaying a login
+func JWT(token string) string {
+	parts := strings.Split(token, ".")
+	var claims struct {
+		Sub string `json:"sub,omitempty"`
+	}
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			json.Unmarshal(payload, &claims)
+		}
+	}
+
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + "." + Placeholder
+}
+
+// Values removes secrets from form or query values in place
+func Values(values url.Values) {
+	for key, vs := range values {
+		for i, v := range vs {
+			if sensitive[key] {
+				vs[i] = Placeholder
+			} else {
+				vs[i] = String(v)
+			}
+		}
+	}
+}
+
+// Query returns a query string with secrets removed
+func Query(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return String(rawQuery)
+	}
+	Values(values)
+	return values.Encode()
+}
+
+// Header returns a copy of the header with secrets removed
+func Header(header http.Header) http.Header {
+	redacted := http.Header{}
+	for key, values := range header {
+		for _, v := range values {
+			redacted.Add(key, String(v))
+		}
+	}
+	for _, key := range Headers {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, Placeholder)
+		}
+	}
+	if location := redacted.Get("Location"); location != "" {
+		if u, err := url.Parse(location); err == nil {
+			u.RawQuery = Query(u.RawQuery)
+			redacted.Set("Location", u.String())
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// jwt builds an unsigned token with the given payload
+func jwt(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestString(t *testing.T) {
+	token := jwt(`{"sub":"12345","email":"jane@example.org"}`)
+	in := `{"id_token":"abc","password":"hunter2","note":"mail jane@example.org"} Bearer ` + token
+
+	out := String(in)
+	for _, secret := range []string{"abc", "hunter2", "jane@example.org", token} {
+		if strings.Contains(out, secret) {
+			t.Errorf("String(%q) = %q, still contains %q", in, out, secret)
+		}
+	}
+	if !strings.Contains(out, `"id_token":"REDACTED"`) {
+		t.Errorf("String(%q) = %q, want id_token redacted", in, out)
+	}
+	if !strings.Contains(out, Email) {
+		t.Errorf("String(%q) = %q, want the email replaced with %s", in, out, Email)
+	}
+}
+
+func TestJWTKeepsOnlySubject(t *testing.T) {
+	parts := strings.Split(JWT(jwt(`{"sub":"12345","email":"jane@example.org"}`)), ".")
+	if len(parts) != 3 || parts[2] != Placeholder {
+		t.Fatalf("JWT returned %v, want an unsigned token", parts)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != `{"sub":"12345"}` {
+		t.Errorf("payload = %s, want only the subject", payload)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	values, err := url.ParseQuery(Query("access_token=abc&id_token=def&q=apple&email=jane@example.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"access_token": {Placeholder},
+		"id_token":     {Placeholder},
+		"q":            {"apple"},
+		"email":        {Email},
+	}
+	for key, v := range want {
+		if values.Get(key) != v[0] {
+			t.Errorf("%s = %q, want %q", key, values.Get(key), v[0])
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	header := Header(http.Header{
+		"Authorization": {"Bearer abc"},
+		"Location":      {"https://example.com/callback?code=abc&state=xyz"},
+		"Accept":        {"application/json"},
+	})
+	if got := header.Get("Authorization"); got != Placeholder {
+		t.Errorf("Authorization = %q, want %q", got, Placeholder)
+	}
+	if got := header.Get("Location"); got != "https://example.com/callback?code=REDACTED&state=xyz" {
+		t.Errorf("Location = %q, want the code redacted", got)
+	}
+	if got := header.Get("Accept"); got != "application/json" {
+		t.Errorf("Accept = %q, want it unchanged", got)
+	}
+}
+
+func TestIsJWT(t *testing.T) {
+	if !IsJWT(jwt(`{"sub":"12345"}`)) {
+		t.Error("IsJWT is false for a JWT")
+	}
+	if IsJWT("Bearer " + jwt(`{"sub":"12345"}`)) {
+		t.Error("IsJWT is true for text containing a JWT")
+	}
+}
@@ -0,0 +1,94 @@
+package myfitnesspal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/seonixx/myfitnesspal/internal/redact"
+)
+
+// maxLoggedBodySize is how much of an error response body is logged
+const maxLoggedBodySize = 1024
+
+// WithLogger logs every request at debug level: its operation, method, path, status and latency,
+// the attempt number of requests resent by WithRetry, and the body of error responses. Headers are never logged, and tokens, client
+// secrets, login credentials, passwords and email addresses are redacted from everything else,
+// by the same rules cassettes are scrubbed with.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// logRequests is middleware that logs requests to the client's logger
+func (c *Client) logRequests(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		if !c.logger.Enabled(ctx, slog.LevelDebug) {
+			return next.RoundTrip(req)
+		}
+
+		info, _ := RequestInfoFrom(ctx)
+		attrs := []slog.Attr{
+			slog.String("operation", info.Operation),
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+		}
+		if req.URL.RawQuery != "" {
+			attrs = append(attrs, slog.String("query", redact.Query(req.URL.RawQuery)))
+		}
+		if info.DomainUserID != "" {
+			attrs = append(attrs, slog.String("user", info.DomainUserID))
+		}
+		if info.Attempt > 1 {
+			attrs = append(attrs, slog.Int("attempt", info.Attempt))
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+
+		if err != nil {
+			attrs = append(attrs, slog.String("error", redact.String(err.Error())))
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "myfitnesspal request failed", attrs...)
+			return resp, err
+		}
+
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			attrs = append(attrs, slog.String("body", peekBody(resp)))
+		}
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "myfitnesspal request", attrs...)
+		return resp, nil
+	})
+}
+
+// peekBody returns the redacted start of a response body, leaving the body unread for the caller
+func peekBody(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if err != nil {
+		return ""
+	}
+
+	// The client asks for gzip itself, so the transport leaves bodies compressed
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return ""
+		}
+		// A truncated body decompresses as far as it goes
+		data, _ = io.ReadAll(zr)
+	}
+	return redact.String(string(data))
+}
@@ -0,0 +1,28 @@
+package myfitnesspal
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPeekBodyRedactsAndLeavesBodyUnread(t *testing.T) {
+	body := `{"error":"invalid_grant","id_token":"abc","username":"jane@example.org"}`
+	resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+
+	logged := peekBody(resp)
+	for _, secret := range []string{"abc", "jane"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("peekBody = %q, still contains %q", logged, secret)
+		}
+	}
+
+	rest, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != body {
+		t.Errorf("body after peekBody = %q, want %q", rest, body)
+	}
+}
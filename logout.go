@@ -0,0 +1,117 @@
+package myfitnesspal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrRevocationUnsupported is returned, wrapped in a *RevocationError, when the identity
+// server does not support revoking a token
+var ErrRevocationUnsupported = errors.New("token revocation is not supported")
+
+// RevocationError reports that the identity server did not revoke a token.
+// Use errors.Is(err, ErrRevocationUnsupported) to tell unsupported revocation from a failure.
+type RevocationError struct {
+	TokenType  string // "refresh_token" or "access_token"
+	StatusCode int    // 0 if the request could not be sent
+	Err        error
+}
+
+// Error describes the failed revocation
+func (e *RevocationError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("error revoking %s: status %d: %v", e.TokenType, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("error revoking %s: %v", e.TokenType, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RevocationError) Unwrap() error {
+	return e.Err
+}
+
+// Logout ends a session. It revokes the refresh token and then the access token on the identity
+// server, clearing each from the session once revoked, and deletes the session from the client's
+// token store. The session is deleted from the store even if revocation fails. Revocation errors
+// are *RevocationError values, joined if both tokens failed.
+func (c *Client) Logout(session *UserSession) error {
+	if session == nil {
+		return fmt.Errorf("no session provided")
+	}
+
+	var errs []error
+
+	// Revoke the refresh token first, as it can be used to mint new access tokens
+	if session.RefreshToken != "" {
+		if err := c.RevokeToken(session.RefreshToken, "refresh_token"); err != nil {
+			errs = append(errs, err)
+		} else {
+			session.RefreshToken = ""
+		}
+	}
+	if session.AccessToken != "" {
+		if err := c.RevokeToken(session.AccessToken, "access_token"); err != nil {
+			errs = append(errs, err)
+		} else {
+			session.AccessToken = ""
+		}
+	}
+
+	if c.tokenStore != nil && session.UserID != "" {
+		if err := c.tokenStore.Delete(session.UserID); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting session from token store: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RevokeToken revokes a refresh or access token on the identity server (RFC 7009).
+// tokenType is "refresh_token" or "access_token".
+func (c *Client) RevokeToken(token, tokenType string) error {
+	if token == "" {
+		return fmt.Errorf("no token provided")
+	}
+
+	data := url.Values{}
+	data.Set("token", token)
+	data.Set("token_type_hint", tokenType)
+	data.Set("client_id", c.clientID)
+	data.Set("client_secret", c.clientSecret)
+
+	var errResp struct {
+		Error string `json:"error"`
+	}
+
+	req := c.identityClient.R().
+		SetBody(data.Encode()).
+		SetError(&errResp)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "RevokeToken", nil)
+
+	// Override Content-Type for form data
+	req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := req.Post("/oauth/revoke")
+	if err != nil {
+		return &RevocationError{TokenType: tokenType, Err: err}
+	}
+
+	switch {
+	case resp.StatusCode() == http.StatusOK:
+		return nil
+	case resp.StatusCode() == http.StatusNotFound,
+		resp.StatusCode() == http.StatusMethodNotAllowed,
+		errResp.Error == "unsupported_token_type":
+		return &RevocationError{TokenType: tokenType, StatusCode: resp.StatusCode(), Err: ErrRevocationUnsupported}
+	}
+
+	reason := errResp.Error
+	if reason == "" {
+		reason = http.StatusText(resp.StatusCode())
+	}
+	return &RevocationError{TokenType: tokenType, StatusCode: resp.StatusCode(), Err: errors.New(reason)}
+}
@@ -0,0 +1,217 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MaxMeals is the maximum number of meals a user can have in their diary
+const MaxMeals = 6
+
+// defaultMealNames are the meal names used when a user has not renamed their meals
+var defaultMealNames = []string{"Breakfast", "Lunch", "Dinner", "Snacks", "Meal 5", "Meal 6"}
+
+// String returns the default name of the meal. Use MealConfig.Format for the user's own name for it.
+func (m MealNumber) String() string {
+	if !m.Valid() {
+		return "Meal(" + strconv.Itoa(int(m)) + ")"
+	}
+	return defaultMealNames[m]
+}
+
+// Valid reports whether the meal is within the range MyFitnessPal supports
+func (m MealNumber) Valid() bool {
+	return m >= 0 && m < MaxMeals
+}
+
+// MarshalJSON encodes the meal as its numeric position, which is what the API expects
+func (m MealNumber) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(m))), nil
+}
+
+// UnmarshalJSON decodes the meal from either its numeric position or its default name
+func (m *MealNumber) UnmarshalJSON(data []byte) error {
+	var position int
+	if err := json.Unmarshal(data, &position); err == nil {
+		*m = MealNumber(position)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid meal: %s", data)
+	}
+	return m.UnmarshalText([]byte(name))
+}
+
+// MarshalText encodes the meal as its default name. Use MealConfig.Named to encode it with the user's own name.
+func (m MealNumber) MarshalText() ([]byte, error) {
+	if !m.Valid() {
+		return nil, fmt.Errorf("invalid meal position: %d", int(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes the meal from its default name or numeric position.
+// Use MealConfig.Parse to decode the user's own names.
+func (m *MealNumber) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	for i, name := range defaultMealNames {
+		if strings.EqualFold(s, name) {
+			*m = MealNumber(i)
+			return nil
+		}
+	}
+
+	position, err := strconv.Atoi(s)
+	if err != nil || !MealNumber(position).Valid() {
+		return fmt.Errorf("invalid meal: %q", s)
+	}
+	*m = MealNumber(position)
+	return nil
+}
+
+// MealConfig represents a user's diary meal configuration
+type MealConfig struct {
+	Names []string `json:"meal_names"`
+}
+
+// Name returns the user's name for the meal
+func (mc *MealConfig) Name(meal MealNumber) (string, error) {
+	if err := mc.Validate(meal); err != nil {
+		return "", err
+	}
+	return mc.Names[meal], nil
+}
+
+// Position resolves one of the user's meal names to its position in the diary
+func (mc *MealConfig) Position(name string) (MealNumber, error) {
+	name = strings.TrimSpace(name)
+	for i, mealName := range mc.Names {
+		if strings.EqualFold(mealName, name) {
+			return MealNumber(i), nil
+		}
+	}
+	return 0, fmt.Errorf("no meal named %q", name)
+}
+
+// Format returns the user's name for the meal, or its default name if the user doesn't have that meal
+func (mc *MealConfig) Format(meal MealNumber) string {
+	if mc.Validate(meal) != nil {
+		return meal.String()
+	}
+	return mc.Names[meal]
+}
+
+// Parse resolves one of the user's meal names, or a numeric position, to a meal in their diary
+func (mc *MealConfig) Parse(s string) (MealNumber, error) {
+	if meal, err := mc.Position(s); err == nil {
+		return meal, nil
+	}
+
+	position, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("no meal named %q", strings.TrimSpace(s))
+	}
+	meal := MealNumber(position)
+	if err := mc.Validate(meal); err != nil {
+		return 0, err
+	}
+	return meal, nil
+}
+
+// Named binds a meal to the user's meal names, so it formats and parses as text with them
+func (mc *MealConfig) Named(meal MealNumber) NamedMeal {
+	return NamedMeal{Meal: meal, Config: mc}
+}
+
+// NamedMeal is a meal that formats and parses as text using a user's meal names
+type NamedMeal struct {
+	Meal   MealNumber
+	Config *MealConfig
+}
+
+// String returns the user's name for the meal
+func (n NamedMeal) String() string {
+	return n.Config.Format(n.Meal)
+}
+
+// MarshalText encodes the meal as the user's name for it
+func (n NamedMeal) MarshalText() ([]byte, error) {
+	if err := n.Config.Validate(n.Meal); err != nil {
+		return nil, err
+	}
+	return []byte(n.Config.Names[n.Meal]), nil
+}
+
+// UnmarshalText decodes the meal from one of the user's meal names or a numeric position.
+// Config must be set first.
+func (n *NamedMeal) UnmarshalText(text []byte) error {
+	if n.Config == nil {
+		return fmt.Errorf("no meal config to parse %q with", text)
+	}
+	meal, err := n.Config.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	n.Meal = meal
+	return nil
+}
+
+// Meals returns the positions of all meals the user has configured
+func (mc *MealConfig) Meals() []MealNumber {
+	meals := make([]MealNumber, len(mc.Names))
+	for i := range mc.Names {
+		meals[i] = MealNumber(i)
+	}
+	return meals
+}
+
+// Validate checks that the meal exists in the user's diary
+func (mc *MealConfig) Validate(meal MealNumber) error {
+	if meal < 0 || int(meal) >= len(mc.Names) {
+		return fmt.Errorf("invalid meal position: %d. User has %d meals", int(meal), len(mc.Names))
+	}
+	return nil
+}
+
+// GetMealConfig fetches the user's meal names from their diary preferences
+func (c *Client) GetMealConfig(session *UserSession) (*MealConfig, error) {
+	var result struct {
+		Item struct {
+			DiaryPreferences MealConfig `json:"diary_preferences"`
+		} `json:"item"`
+	}
+
+	req := c.apiClient.R().
+		SetQueryParam("fields[]", "diary_preferences")
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "GetMealConfig", session)
+
+	resp, err := req.Get("/v2/users/" + session.DomainUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meal config: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get meal config failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse meal config response: %w", err)
+	}
+
+	config := result.Item.DiaryPreferences
+	if len(config.Names) == 0 {
+		config.Names = append([]string(nil), defaultMealNames[:Snacks+1]...)
+	}
+	if len(config.Names) > MaxMeals {
+		config.Names = config.Names[:MaxMeals]
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,49 @@
+package myfitnesspal
+
+import "testing"
+
+func TestMealConfigFormatAndParse(t *testing.T) {
+	meals := &MealConfig{Names: []string{"Breakfast", "Lunch", "Dinner", "Snacks", "Pre-workout"}}
+
+	if got := meals.Format(MealNumber(4)); got != "Pre-workout" {
+		t.Errorf("Format(4) = %q, want %q", got, "Pre-workout")
+	}
+	if got := meals.Format(MealNumber(5)); got != "Meal 6" {
+		t.Errorf("Format(5) = %q, want the default name %q", got, "Meal 6")
+	}
+
+	for _, s := range []string{"Pre-workout", "pre-workout", "4"} {
+		if meal, err := meals.Parse(s); err != nil || meal != 4 {
+			t.Errorf("Parse(%q) = %v, %v, want 4", s, meal, err)
+		}
+	}
+	for _, s := range []string{"Meal 5", "5", "Supper"} {
+		if meal, err := meals.Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", s, meal)
+		}
+	}
+}
+
+func TestNamedMealText(t *testing.T) {
+	meals := &MealConfig{Names: []string{"Early", "Midday", "Evening", "Snacks"}}
+
+	text, err := meals.Named(Dinner).MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "Evening" {
+		t.Errorf("MarshalText = %q, want %q", text, "Evening")
+	}
+
+	named := NamedMeal{Config: meals}
+	if err := named.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if named.Meal != Dinner {
+		t.Errorf("UnmarshalText(%q) = %v, want %v", text, named.Meal, Dinner)
+	}
+
+	if err := new(NamedMeal).UnmarshalText(text); err == nil {
+		t.Error("UnmarshalText without a Config succeeded")
+	}
+}
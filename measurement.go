@@ -0,0 +1,214 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// MeasurementType is a body measurement a user can track
+type MeasurementType string
+
+const (
+	Weight  MeasurementType = "weight"
+	BodyFat MeasurementType = "body_fat"
+	Waist   MeasurementType = "waist"
+	Neck    MeasurementType = "neck"
+	Hips    MeasurementType = "hips"
+)
+
+// MeasurementUnit is the unit a measurement value is recorded in
+type MeasurementUnit string
+
+const (
+	Kilograms   MeasurementUnit = "kilograms"
+	Pounds      MeasurementUnit = "pounds"
+	Stones      MeasurementUnit = "stones"
+	Centimeters MeasurementUnit = "centimeters"
+	Inches      MeasurementUnit = "inches"
+	Percent     MeasurementUnit = "percent"
+)
+
+// baseUnitsPer is the number of base units (kilograms, centimeters or percent) in one of each unit
+var baseUnitsPer = map[MeasurementUnit]struct {
+	base  MeasurementUnit
+	ratio float64
+}{
+	Kilograms:   {Kilograms, 1},
+	Pounds:      {Kilograms, kilogramsPerPound},
+	Stones:      {Kilograms, kilogramsPerPound * poundsPerStone},
+	Centimeters: {Centimeters, 1},
+	Inches:      {Centimeters, centimetersPerInch},
+	Percent:     {Percent, 1},
+}
+
+// ConvertMeasurement converts a measurement value between units of the same kind
+func ConvertMeasurement(value float64, from, to MeasurementUnit) (float64, error) {
+	fromUnit, ok := baseUnitsPer[from]
+	if !ok {
+		return 0, fmt.Errorf("invalid measurement unit: %s", from)
+	}
+	toUnit, ok := baseUnitsPer[to]
+	if !ok {
+		return 0, fmt.Errorf("invalid measurement unit: %s", to)
+	}
+	if fromUnit.base != toUnit.base {
+		return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+	return value * fromUnit.ratio / toUnit.ratio, nil
+}
+
+// defaultMeasurementUnits are the units each measurement type is recorded in when none is given
+var defaultMeasurementUnits = map[MeasurementType]MeasurementUnit{
+	Weight:  Pounds,
+	BodyFat: Percent,
+	Waist:   Inches,
+	Neck:    Inches,
+	Hips:    Inches,
+}
+
+// Measurement represents a body measurement logged on a date
+type Measurement struct {
+	ID    string          `json:"id,omitempty"`
+	Type  MeasurementType `json:"type"`
+	Date  Date            `json:"date"`
+	Value float64         `json:"value"`
+	Unit  MeasurementUnit `json:"unit"`
+}
+
+// In returns the measurement value converted to the given unit
+func (m Measurement) In(unit MeasurementUnit) (float64, error) {
+	return ConvertMeasurement(m.Value, m.Unit, unit)
+}
+
+// Kilograms returns a weight measurement in kilograms
+func (m Measurement) Kilograms() (float64, error) {
+	return m.In(Kilograms)
+}
+
+// Pounds returns a weight measurement in pounds
+func (m Measurement) Pounds() (float64, error) {
+	return m.In(Pounds)
+}
+
+// Mass returns a weight measurement as a Mass
+func (m Measurement) Mass() (Mass, error) {
+	pounds, err := m.In(Pounds)
+	if err != nil {
+		return 0, err
+	}
+	return MassFromPounds(pounds), nil
+}
+
+// Length returns a body measurement such as waist or neck as a Length
+func (m Measurement) Length() (Length, error) {
+	inches, err := m.In(Inches)
+	if err != nil {
+		return 0, err
+	}
+	return LengthFromInches(inches), nil
+}
+
+// GetMeasurements fetches the user's measurements of a type from start to end inclusive
+func (c *Client) GetMeasurements(session *UserSession, measurementType MeasurementType, start, end Date) ([]Measurement, error) {
+	return c.getMeasurements(session, "GetMeasurements", measurementType, map[string]string{
+		"type":       string(measurementType),
+		"start_date": start.String(),
+		"end_date":   end.String(),
+	})
+}
+
+// GetLatestMeasurement fetches the user's most recent measurement of a type.
+// It returns nil if the user has never logged one.
+func (c *Client) GetLatestMeasurement(session *UserSession, measurementType MeasurementType) (*Measurement, error) {
+	measurements, err := c.getMeasurements(session, "GetLatestMeasurement", measurementType, map[string]string{
+		"type":        string(measurementType),
+		"most_recent": "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(measurements) == 0 {
+		return nil, nil
+	}
+	return &measurements[len(measurements)-1], nil
+}
+
+// getMeasurements fetches measurements matching the query and sorts them by date
+func (c *Client) getMeasurements(session *UserSession, operation string, measurementType MeasurementType, query map[string]string) ([]Measurement, error) {
+	req := c.apiClient.R().
+		SetQueryParams(query)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, operation, session)
+
+	resp, err := req.Get("/v2/measurements")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get measurements failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Items []Measurement `json:"items"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse measurements response: %w", err)
+	}
+
+	for i := range result.Items {
+		if result.Items[i].Unit == "" {
+			result.Items[i].Unit = defaultMeasurementUnits[measurementType]
+		}
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].Date.Before(result.Items[j].Date)
+	})
+
+	return result.Items, nil
+}
+
+// LogMeasurement records a measurement, such as a new weigh-in.
+// The unit defaults to the usual unit for the measurement type if not set.
+func (c *Client) LogMeasurement(session *UserSession, measurement Measurement) (*Measurement, error) {
+	if measurement.Date.IsZero() {
+		return nil, fmt.Errorf("no measurement date provided")
+	}
+	if measurement.Unit == "" {
+		measurement.Unit = defaultMeasurementUnits[measurement.Type]
+	}
+	if _, ok := baseUnitsPer[measurement.Unit]; !ok {
+		return nil, fmt.Errorf("invalid measurement unit: %s", measurement.Unit)
+	}
+
+	var result struct {
+		Items []Measurement `json:"items"`
+	}
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"items": []Measurement{measurement},
+		}).
+		SetResult(&result)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "LogMeasurement", session)
+
+	resp, err := req.Post("/v2/measurements")
+	if err != nil {
+		return nil, fmt.Errorf("failed to log measurement: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("log measurement failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	if len(result.Items) == 0 {
+		return &measurement, nil
+	}
+	return &result.Items[0], nil
+}
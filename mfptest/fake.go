@@ -0,0 +1,541 @@
+package mfptest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+// Fake is an in-memory implementation of the client's service interfaces, for tests of
+// code that depends on myfitnesspal.Authenticator, UserService, FoodService or DiaryService.
+// Unlike Server it does not use HTTP at all.
+type Fake struct {
+	mu       sync.Mutex
+	nextID   int
+	accounts map[string]*fakeAccount // By identity user ID
+	tokens   map[string]string       // Access token to identity user ID
+	refresh  map[string]string       // Refresh token to identity user ID
+	foods    []myfitnesspal.FoodItem
+	diary    []fakeEntry
+	errors   map[string]error
+}
+
+// fakeAccount is a user known to a Fake
+type fakeAccount struct {
+	login *User
+	user  myfitnesspal.User // Profile as changed by UpdateProfile
+}
+
+// currentUser returns the account's user, with the name and email from its login so changes
+// made through the *User returned by AddUser show up, as they do on a Server
+func (a *fakeAccount) currentUser() myfitnesspal.User {
+	identity := decodeUser(a.login)
+	user := a.user
+	user.ProfileEmails = identity.ProfileEmails
+	user.Profile.FirstName = identity.Profile.FirstName
+	user.Profile.LastName = identity.Profile.LastName
+	return user
+}
+
+// decodeUser builds a user the same way the client decodes it from the identity API
+func decodeUser(login *User) myfitnesspal.User {
+	var user myfitnesspal.User
+	data, _ := json.Marshal(identityUser(*login))
+	json.Unmarshal(data, &user)
+	return user
+}
+
+// fakeEntry is a diary entry and the domain user ID it belongs to
+type fakeEntry struct {
+	userID string
+	entry  myfitnesspal.FoodEntry
+}
+
+var (
+	_ myfitnesspal.Authenticator = (*Fake)(nil)
+	_ myfitnesspal.UserService   = (*Fake)(nil)
+	_ myfitnesspal.FoodService   = (*Fake)(nil)
+	_ myfitnesspal.DiaryService  = (*Fake)(nil)
+)
+
+// NewFake creates an empty Fake
+func NewFake() *Fake {
+	return &Fake{
+		accounts: map[string]*fakeAccount{},
+		tokens:   map[string]string{},
+		refresh:  map[string]string{},
+		errors:   map[string]error{},
+	}
+}
+
+// AddUser creates a user that can log in with the given email and password
+func (f *Fake) AddUser(email, password string) *User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	login := &User{
+		ID:           strconv.Itoa(100000 + f.newID()),
+		DomainUserID: hex.EncodeToString(randomBytes(8)),
+		Email:        email,
+		Password:     password,
+		FirstName:    "Test",
+		LastName:     "User",
+		MealNames:    defaultMealNames(),
+	}
+
+	f.accounts[login.ID] = &fakeAccount{login: login, user: decodeUser(login)}
+	return login
+}
+
+// SetMealNames renames a user's diary meals, as a premium user can
+func (f *Fake) SetMealNames(userID string, names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if account, ok := f.accounts[userID]; ok {
+		account.login.MealNames = names
+	}
+}
+
+// AddFood adds a food to the database and returns it with its ID and version set
+func (f *Fake) AddFood(food myfitnesspal.FoodItem) myfitnesspal.FoodItem {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addFood(food)
+}
+
+// FailWith makes every call to the named method, e.g. "Login" or "AddFoodToDiary", return err.
+// Pass a nil error to make it succeed again.
+func (f *Fake) FailWith(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.errors, method)
+		return
+	}
+	f.errors[method] = err
+}
+
+// Login logs a user in with their email and password
+func (f *Fake) Login(username, password string) (*myfitnesspal.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errors["Login"]; err != nil {
+		return nil, err
+	}
+
+	for _, account := range f.accounts {
+		if strings.EqualFold(account.login.Email, username) && account.login.Password == password {
+			return f.newSession(account), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected response status: 401")
+}
+
+// RefreshUserToken exchanges a refresh token for a new session
+func (f *Fake) RefreshUserToken(mfpUserID string, refreshToken string) (*myfitnesspal.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errors["RefreshUserToken"]; err != nil {
+		return nil, err
+	}
+	if refreshToken == "" {
+		return nil, fmt.Errorf("no refresh token provided")
+	}
+
+	userID, ok := f.refresh[refreshToken]
+	if !ok || (mfpUserID != "" && mfpUserID != userID) {
+		return nil, fmt.Errorf("unexpected status code: 400")
+	}
+	delete(f.refresh, refreshToken)
+	return f.newSession(f.accounts[userID]), nil
+}
+
+// Logout revokes the session's tokens
+func (f *Fake) Logout(session *myfitnesspal.UserSession) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errors["Logout"]; err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("no session provided")
+	}
+
+	delete(f.refresh, session.RefreshToken)
+	delete(f.tokens, session.AccessToken)
+	session.RefreshToken = ""
+	session.AccessToken = ""
+	return nil
+}
+
+// GetUser returns the session's user
+func (f *Fake) GetUser(session *myfitnesspal.UserSession) (*myfitnesspal.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("GetUser", session)
+	if err != nil {
+		return nil, err
+	}
+	user := account.currentUser()
+	return &user, nil
+}
+
+// UpdateProfile applies the fields set in patch to the session user's profile
+func (f *Fake) UpdateProfile(session *myfitnesspal.UserSession, patch myfitnesspal.ProfilePatch) (*myfitnesspal.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("UpdateProfile", session)
+	if err != nil {
+		return nil, err
+	}
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("no profile fields to update")
+	}
+
+	profile := &account.user.Profile
+	if patch.DisplayName != nil {
+		profile.DisplayName = *patch.DisplayName
+	}
+	if patch.Height != nil {
+		profile.Height = *patch.Height
+	}
+	if patch.Weight != nil {
+		profile.Weight = *patch.Weight
+	}
+	if patch.Birthdate != nil {
+		profile.Birthdate = patch.Birthdate.String()
+	}
+	if patch.Gender != nil {
+		profile.Gender = *patch.Gender
+	}
+	if patch.Locale != nil {
+		profile.Locale = *patch.Locale
+	}
+	if patch.Location != nil {
+		profile.Location = *patch.Location
+	}
+
+	user := account.currentUser()
+	return &user, nil
+}
+
+// CreateFood adds a food to the database
+func (f *Fake) CreateFood(session *myfitnesspal.UserSession, food myfitnesspal.FoodItem) (*myfitnesspal.CreateFoodResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("CreateFood", session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Foods belong to the user who created them
+	food.UserID = account.login.DomainUserID
+	food = f.addFood(food)
+
+	var response myfitnesspal.CreateFoodResponse
+	data, _ := json.Marshal(map[string]interface{}{"items": []myfitnesspal.FoodItem{food}})
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SearchFood finds foods whose description or brand name contains the query
+func (f *Fake) SearchFood(session *myfitnesspal.UserSession, params myfitnesspal.SearchFoodRequest) ([]myfitnesspal.FoodSearchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("SearchFood", session)
+	if err != nil {
+		return nil, err
+	}
+
+	maxItems := 25
+	if params.MaxItems != nil {
+		maxItems = *params.MaxItems
+	}
+	scope := "all"
+	if params.Scope != nil {
+		scope = *params.Scope
+	}
+	if scope != "all" && scope != "user" {
+		return nil, fmt.Errorf("invalid scope: %s. Must be 'all' or 'user'", scope)
+	}
+
+	query := strings.ToLower(params.Query)
+	results := []myfitnesspal.FoodSearchResult{}
+	for _, food := range f.foods {
+		if len(results) >= maxItems {
+			break
+		}
+		own := food.UserID == account.login.ID || food.UserID == account.login.DomainUserID
+		if scope == "user" && !own || !food.Public && !own {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(food.BrandName+" "+food.Description), query) {
+			continue
+		}
+
+		var result myfitnesspal.FoodSearchResult
+		data, _ := json.Marshal(map[string]interface{}{"item": food, "type": "food"})
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// RecentFoods returns the foods most recently logged to a meal, newest first
+func (f *Fake) RecentFoods(session *myfitnesspal.UserSession, meal myfitnesspal.MealNumber) ([]myfitnesspal.LoggedFood, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("RecentFoods", session)
+	if err != nil {
+		return nil, err
+	}
+	meals := myfitnesspal.MealConfig{Names: account.login.MealNames}
+	if err := meals.Validate(meal); err != nil {
+		return nil, err
+	}
+
+	foods := f.loggedFoods(account, func(entry myfitnesspal.FoodEntry) bool {
+		return entry.MealPosition == meal
+	})
+	sort.SliceStable(foods, func(i, j int) bool {
+		return foods[i].LastUsed.After(foods[j].LastUsed)
+	})
+	return foods, nil
+}
+
+// FrequentFoods returns the foods logged most often, most frequent first
+func (f *Fake) FrequentFoods(session *myfitnesspal.UserSession) ([]myfitnesspal.LoggedFood, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("FrequentFoods", session)
+	if err != nil {
+		return nil, err
+	}
+
+	foods := f.loggedFoods(account, func(myfitnesspal.FoodEntry) bool { return true })
+	sort.SliceStable(foods, func(i, j int) bool {
+		if foods[i].Count != foods[j].Count {
+			return foods[i].Count > foods[j].Count
+		}
+		return foods[i].LastUsed.After(foods[j].LastUsed)
+	})
+	return foods, nil
+}
+
+// AddFoodToDiary adds a food entry to the session user's diary
+func (f *Fake) AddFoodToDiary(session *myfitnesspal.UserSession, params myfitnesspal.FoodDiaryAddRequest) (*myfitnesspal.FoodDiaryAddResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("AddFoodToDiary", session)
+	if err != nil {
+		return nil, err
+	}
+	if params.Date.IsZero() {
+		return nil, fmt.Errorf("no diary date provided")
+	}
+	meals := myfitnesspal.MealConfig{Names: account.login.MealNames}
+	if err := meals.Validate(params.MealPosition); err != nil {
+		return nil, err
+	}
+
+	var food *myfitnesspal.FoodItem
+	for i := range f.foods {
+		if f.foods[i].ID == params.Food.ID {
+			food = &f.foods[i]
+		}
+	}
+	if food == nil {
+		return nil, fmt.Errorf("add food to diary failed with status 404: unknown food %s", params.Food.ID)
+	}
+
+	multiplier := params.ServingSize.NutritionMultiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	entry := myfitnesspal.FoodEntry{
+		ID:                  strconv.Itoa(f.newID()),
+		Type:                "food_entry",
+		Date:                params.Date,
+		MealName:            meals.Format(params.MealPosition),
+		MealPosition:        params.MealPosition,
+		Food:                *food,
+		ServingSize:         params.ServingSize,
+		Servings:            params.Servings,
+		NutritionalContents: food.NutritionalContents.Scale(params.Servings * multiplier),
+		ImageIDs:            params.ImageIDs,
+		Tags:                params.Tags,
+	}
+	if params.Geolocation != nil {
+		entry.Geolocation = *params.Geolocation
+	}
+	if params.ConsumedAt != nil {
+		consumedAt := params.ConsumedAt.Format(time.RFC3339)
+		entry.ConsumedAt = &consumedAt
+	}
+
+	f.diary = append(f.diary, fakeEntry{userID: account.login.DomainUserID, entry: entry})
+	return &myfitnesspal.FoodDiaryAddResponse{Items: []myfitnesspal.FoodEntry{entry}}, nil
+}
+
+// GetFoodDiary returns the session user's food entries for a date
+func (f *Fake) GetFoodDiary(session *myfitnesspal.UserSession, date myfitnesspal.Date) ([]myfitnesspal.FoodEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("GetFoodDiary", session)
+	if err != nil {
+		return nil, err
+	}
+	return f.diaryRange(account, date, date), nil
+}
+
+// GetFoodDiaryRange returns the session user's food entries from start to end inclusive
+func (f *Fake) GetFoodDiaryRange(session *myfitnesspal.UserSession, start, end myfitnesspal.Date) ([]myfitnesspal.FoodEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("GetFoodDiaryRange", session)
+	if err != nil {
+		return nil, err
+	}
+	return f.diaryRange(account, start, end), nil
+}
+
+// DeleteDiaryEntry deletes an entry from the session user's diary
+func (f *Fake) DeleteDiaryEntry(session *myfitnesspal.UserSession, entryID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	account, err := f.check("DeleteDiaryEntry", session)
+	if err != nil {
+		return err
+	}
+	if entryID == "" {
+		return fmt.Errorf("no diary entry ID provided")
+	}
+
+	for i, stored := range f.diary {
+		if stored.userID == account.login.DomainUserID && stored.entry.ID == entryID {
+			f.diary = append(f.diary[:i], f.diary[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("delete diary entry failed with status 404: diary entry not found")
+}
+
+// check returns the session's account, or the error configured for the method. f.mu must be held.
+func (f *Fake) check(method string, session *myfitnesspal.UserSession) (*fakeAccount, error) {
+	if err := f.errors[method]; err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("no session provided")
+	}
+
+	userID, ok := f.tokens[session.AccessToken]
+	if !ok {
+		return nil, fmt.Errorf("unexpected status code: 401")
+	}
+	return f.accounts[userID], nil
+}
+
+// diaryRange returns the account's food entries from start to end inclusive, in date order.
+// f.mu must be held.
+func (f *Fake) diaryRange(account *fakeAccount, start, end myfitnesspal.Date) []myfitnesspal.FoodEntry {
+	entries := []myfitnesspal.FoodEntry{}
+	for _, stored := range f.diary {
+		if stored.userID == account.login.DomainUserID && !stored.entry.Date.Before(start) && !stored.entry.Date.After(end) {
+			entries = append(entries, stored.entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	return entries
+}
+
+// newSession issues tokens for an account. f.mu must be held.
+func (f *Fake) newSession(account *fakeAccount) *myfitnesspal.UserSession {
+	session := &myfitnesspal.UserSession{
+		UserID:       account.login.ID,
+		Email:        account.login.Email,
+		FirstName:    account.login.FirstName,
+		LastName:     account.login.LastName,
+		DomainUserID: account.login.DomainUserID,
+		AccessToken:  hex.EncodeToString(randomBytes(16)),
+		RefreshToken: hex.EncodeToString(randomBytes(16)),
+		ExpiresAt:    time.Now().Add(tokenLifetime * time.Second),
+	}
+	f.tokens[session.AccessToken] = account.login.ID
+	f.refresh[session.RefreshToken] = account.login.ID
+	return session
+}
+
+// addFood stores a food, assigning its ID and version. f.mu must be held.
+func (f *Fake) addFood(food myfitnesspal.FoodItem) myfitnesspal.FoodItem {
+	food.ID = strconv.Itoa(f.newID())
+	food.Version = food.ID
+	f.foods = append(f.foods, food)
+	return food
+}
+
+// loggedFoods returns each food in the account's diary matching include, with the
+// serving it was last logged with. f.mu must be held.
+func (f *Fake) loggedFoods(account *fakeAccount, include func(myfitnesspal.FoodEntry) bool) []myfitnesspal.LoggedFood {
+	byID := map[string]*myfitnesspal.LoggedFood{}
+	var order []string
+	for _, stored := range f.diary {
+		entry := stored.entry
+		if stored.userID != account.login.DomainUserID || !include(entry) {
+			continue
+		}
+
+		logged, ok := byID[entry.Food.ID]
+		if !ok {
+			logged = &myfitnesspal.LoggedFood{Food: entry.Food}
+			byID[entry.Food.ID] = logged
+			order = append(order, entry.Food.ID)
+		}
+		logged.Count++
+		if !entry.Date.Before(logged.LastUsed) {
+			logged.ServingSize = entry.ServingSize
+			logged.Servings = entry.Servings
+			logged.MealPosition = entry.MealPosition
+			logged.LastUsed = entry.Date
+		}
+	}
+
+	foods := make([]myfitnesspal.LoggedFood, 0, len(order))
+	for _, id := range order {
+		foods = append(foods, *byID[id])
+	}
+	return foods
+}
+
+// newID returns a new unique ID. f.mu must be held.
+func (f *Fake) newID() int {
+	f.nextID++
+	return f.nextID
+}
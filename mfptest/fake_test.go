@@ -0,0 +1,139 @@
+package mfptest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+// logFood adds a food to the Fake and logs a serving of it on date
+func logFood(t *testing.T, f *Fake, session *myfitnesspal.UserSession, date myfitnesspal.Date) myfitnesspal.FoodEntry {
+	t.Helper()
+
+	food := f.AddFood(myfitnesspal.FoodItem{Description: "Apple", Public: true})
+	req := myfitnesspal.FoodDiaryAddRequest{Type: "food_entry", Date: date, Servings: 1, MealPosition: myfitnesspal.Breakfast}
+	req.Food.ID = food.ID
+	resp, err := f.AddFoodToDiary(session, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Items[0]
+}
+
+func TestFakeDiary(t *testing.T) {
+	f := NewFake()
+	f.AddUser("test@example.com", "password")
+	session, err := f.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := myfitnesspal.NewDate(2026, 3, 1)
+	entry := logFood(t, f, session, day)
+	logFood(t, f, session, day.AddDays(1))
+
+	entries, err := f.GetFoodDiary(session, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Fatalf("GetFoodDiary = %+v, want the one entry on %s", entries, day)
+	}
+
+	entries, err = f.GetFoodDiaryRange(session, day, day.AddDays(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("GetFoodDiaryRange returned %d entries, want 2", len(entries))
+	}
+
+	if err := f.DeleteDiaryEntry(session, entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := f.GetFoodDiary(session, day); len(entries) != 0 {
+		t.Errorf("entry still in the diary after DeleteDiaryEntry")
+	}
+}
+
+func TestFakeFailWithAppliesOnlyToTheNamedMethod(t *testing.T) {
+	f := NewFake()
+	f.AddUser("test@example.com", "password")
+	session, err := f.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := myfitnesspal.NewDate(2026, 3, 1)
+	errFailed := errors.New("failed")
+
+	f.FailWith("GetFoodDiaryRange", errFailed)
+	if _, err := f.GetFoodDiaryRange(session, day, day); !errors.Is(err, errFailed) {
+		t.Errorf("GetFoodDiaryRange error = %v, want %v", err, errFailed)
+	}
+	if _, err := f.GetFoodDiary(session, day); err != nil {
+		t.Errorf("GetFoodDiary failed with %v after FailWith(\"GetFoodDiaryRange\")", err)
+	}
+
+	f.FailWith("GetFoodDiaryRange", nil)
+	f.FailWith("GetFoodDiary", errFailed)
+	if _, err := f.GetFoodDiary(session, day); !errors.Is(err, errFailed) {
+		t.Errorf("GetFoodDiary error = %v, want %v", err, errFailed)
+	}
+	if _, err := f.GetFoodDiaryRange(session, day, day); err != nil {
+		t.Errorf("GetFoodDiaryRange failed with %v after FailWith(\"GetFoodDiary\")", err)
+	}
+}
+
+func TestFakeNamesEntriesWithTheUsersMeals(t *testing.T) {
+	f := NewFake()
+	user := f.AddUser("test@example.com", "password")
+	f.SetMealNames(user.ID, "Early", "Midday", "Evening", "Snacks")
+	session, err := f.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := logFood(t, f, session, myfitnesspal.NewDate(2026, 3, 1)); entry.MealName != "Early" {
+		t.Errorf("MealName = %q, want %q", entry.MealName, "Early")
+	}
+}
+
+func TestFakeAddUserReturnsTheStoredUser(t *testing.T) {
+	f := NewFake()
+	user := f.AddUser("test@example.com", "password")
+	user.FirstName = "Changed"
+	user.Password = "new password"
+
+	if _, err := f.Login("test@example.com", "password"); err == nil {
+		t.Error("Login with the old password succeeded after changing it")
+	}
+	session, err := f.Login("test@example.com", "new password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.GetUser(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Profile.FirstName != "Changed" {
+		t.Errorf("GetUser first name = %q, want %q", got.Profile.FirstName, "Changed")
+	}
+}
+
+func TestFakeRecentFoodsUsesTheUsersMeals(t *testing.T) {
+	f := NewFake()
+	user := f.AddUser("test@example.com", "password")
+	f.SetMealNames(user.ID, "Breakfast", "Lunch", "Dinner", "Snacks", "Pre-workout")
+	session, err := f.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.RecentFoods(session, 4); err != nil {
+		t.Errorf("RecentFoods for the user's fifth meal: %v", err)
+	}
+	if _, err := f.RecentFoods(session, 5); err == nil {
+		t.Error("RecentFoods for a meal the user doesn't have succeeded")
+	}
+}
@@ -0,0 +1,753 @@
+// Package mfptest provides an in-process fake of the MyFitnessPal identity and API servers,
+// so code using myfitnesspal.Client can be tested without live credentials.
+//
+// The fake implements login, token refresh and revocation, user info, food creation and search, and diary
+// reads and writes, with all state held in memory:
+//
+//	srv := mfptest.NewServer()
+//	defer srv.Close()
+//	srv.AddUser("test@example.com", "password")
+//
+//	client, err := srv.NewClient()
+//	session, err := client.Login("test@example.com", "password")
+package mfptest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/seonixx/myfitnesspal"
+)
+
+const (
+	// ClientID is the OAuth client ID the fake accepts
+	ClientID = "mfptest-client"
+	// ClientSecret is the OAuth client secret the fake accepts
+	ClientSecret = "mfptest-secret"
+
+	// tokenLifetime is how long issued access tokens are valid for, in seconds
+	tokenLifetime = 3600
+)
+
+// User represents a user account on the fake server
+type User struct {
+	ID           string // Identity user ID, UserSession.UserID
+	DomainUserID string // MFP user ID, UserSession.DomainUserID
+	Email        string
+	Password     string
+	FirstName    string
+	LastName     string
+	MealNames    []string // Diary meal names, Breakfast, Lunch, Dinner and Snacks unless changed
+}
+
+// Server is a fake MyFitnessPal server. It serves both the identity and API endpoints.
+type Server struct {
+	// URL is the base URL of the server, for both identity and API requests
+	URL string
+
+	srv        *httptest.Server
+	signingKey []byte
+	keyID      string
+
+	mu       sync.Mutex
+	nextID   int
+	users    map[string]*User  // By identity user ID
+	tokens   map[string]string // Access token to identity user ID, "" for client tokens
+	refresh  map[string]string // Refresh token to identity user ID
+	codes    map[string]string // Authorization code to identity user ID
+	foods    map[string]myfitnesspal.FoodItem
+	diary    []diaryItem
+	requests []string
+}
+
+// diaryItem is a stored diary item
+type diaryItem struct {
+	id     string
+	userID string // Domain user ID
+	date   string
+	kind   string
+	source string
+	body   map[string]interface{}
+}
+
+// NewServer starts a fake server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		signingKey: randomBytes(64),
+		keyID:      "mfptest-key",
+		users:      map[string]*User{},
+		tokens:     map[string]string{},
+		refresh:    map[string]string{},
+		codes:      map[string]string{},
+		foods:      map[string]myfitnesspal.FoodItem{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/token", s.handleToken)
+	mux.HandleFunc("POST /oauth/authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /oauth/revoke", s.handleRevoke)
+	mux.HandleFunc("GET /clientKeys", s.handleClientKeys)
+	mux.HandleFunc("GET /users/{id}", s.handleGetUser)
+	mux.HandleFunc("GET /v2/users/{id}", s.handleGetPreferences)
+	mux.HandleFunc("POST /v2/foods", s.handleCreateFood)
+	mux.HandleFunc("GET /v2/search/nutrition", s.handleSearch)
+	mux.HandleFunc("GET /v2/diary", s.handleGetDiary)
+	mux.HandleFunc("POST /v2/diary", s.handleAddDiary)
+	mux.HandleFunc("DELETE /v2/diary/{id}", s.handleDeleteDiary)
+
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Option returns a client option pointing a myfitnesspal.Client at the server
+func (s *Server) Option() myfitnesspal.Option {
+	return myfitnesspal.WithBaseURLs(s.URL, s.URL)
+}
+
+// NewClient creates a client connected to the server
+func (s *Server) NewClient(opts ...myfitnesspal.Option) (*myfitnesspal.Client, error) {
+	return myfitnesspal.NewClient(ClientID, ClientSecret, append([]myfitnesspal.Option{s.Option()}, opts...)...)
+}
+
+// AddUser creates a user that can log in with the given email and password
+func (s *Server) AddUser(email, password string) *User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := &User{
+		ID:           strconv.Itoa(100000 + s.newID()),
+		DomainUserID: hex.EncodeToString(randomBytes(8)),
+		Email:        email,
+		Password:     password,
+		FirstName:    "Test",
+		LastName:     "User",
+		MealNames:    defaultMealNames(),
+	}
+	s.users[user.ID] = user
+	return user
+}
+
+// SetMealNames renames a user's diary meals, as a premium user can
+func (s *Server) SetMealNames(userID string, names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.users[userID]; ok {
+		user.MealNames = names
+	}
+}
+
+// AddFood adds a food to the database, as if created by another user, and returns it with its ID and version set
+func (s *Server) AddFood(food myfitnesspal.FoodItem) myfitnesspal.FoodItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addFood(food)
+}
+
+// Requests returns the method and path of every request the server has received, in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// RevokeTokens invalidates every access and refresh token issued to a user
+func (s *Server) RevokeTokens(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for token, id := range s.tokens {
+		if id == userID {
+			delete(s.tokens, token)
+		}
+	}
+	for token, id := range s.refresh {
+		if id == userID {
+			delete(s.refresh, token)
+		}
+	}
+}
+
+// handleToken issues tokens for the client credentials, authorization code and refresh token grants
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+	if r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid client")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		token := hex.EncodeToString(randomBytes(16))
+		s.tokens[token] = ""
+		writeJSON(w, http.StatusOK, myfitnesspal.TokenResponse{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			ExpiresIn:   tokenLifetime,
+		})
+	case "authorization_code":
+		userID, ok := s.codes[r.PostForm.Get("code")]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid code")
+			return
+		}
+		delete(s.codes, r.PostForm.Get("code"))
+		s.writeUserTokens(w, userID)
+	case "refresh_token":
+		userID, ok := s.refresh[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid refresh token")
+			return
+		}
+		delete(s.refresh, r.PostForm.Get("refresh_token"))
+		s.writeUserTokens(w, userID)
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported grant type")
+	}
+}
+
+// handleRevoke revokes an access or refresh token. Unknown tokens succeed, as in RFC 7009.
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+	if r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid client")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := r.PostForm.Get("token")
+	switch r.PostForm.Get("token_type_hint") {
+	case "refresh_token":
+		delete(s.refresh, token)
+	case "access_token":
+		delete(s.tokens, token)
+	default:
+		delete(s.refresh, token)
+		delete(s.tokens, token)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeUserTokens issues and writes a new set of tokens for a user. s.mu must be held.
+func (s *Server) writeUserTokens(w http.ResponseWriter, userID string) {
+	accessToken := hex.EncodeToString(randomBytes(16))
+	refreshToken := hex.EncodeToString(randomBytes(16))
+	s.tokens[accessToken] = userID
+	s.refresh[refreshToken] = userID
+
+	idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{"sub": userID}).SignedString(s.signingKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, myfitnesspal.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    tokenLifetime,
+		RefreshToken: refreshToken,
+		IDToken:      idToken,
+	})
+}
+
+// handleAuthorize checks signed login credentials and redirects with an authorization code
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.tokenUser(r); !ok {
+		writeError(w, http.StatusUnauthorized, "invalid client token")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+
+	var claims struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		jwt.RegisteredClaims
+	}
+	_, err := jwt.ParseWithClaims(r.PostForm.Get("credentials"), &claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Header["kid"] != s.keyID {
+			return nil, fmt.Errorf("unknown key ID: %v", token.Header["kid"])
+		}
+		return s.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid credentials: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, claims.Username) && user.Password == claims.Password {
+			code := hex.EncodeToString(randomBytes(16))
+			s.codes[code] = user.ID
+
+			redirect, err := url.Parse(r.PostForm.Get("redirect_uri"))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid redirect_uri")
+				return
+			}
+			query := redirect.Query()
+			query.Set("code", code)
+			redirect.RawQuery = query.Encode()
+
+			w.Header().Set("Location", redirect.String())
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+	}
+
+	writeError(w, http.StatusUnauthorized, "invalid username or password")
+}
+
+// handleClientKeys returns the key used to sign login credentials
+func (s *Server) handleClientKeys(w http.ResponseWriter, r *http.Request) {
+	auth := base64.StdEncoding.EncodeToString([]byte(ClientID + ":" + ClientSecret))
+	if r.Header.Get("Authorization") != "Basic "+auth {
+		writeError(w, http.StatusUnauthorized, "invalid client")
+		return
+	}
+
+	var key myfitnesspal.ClientKey
+	key.Key.Kty = "oct"
+	key.Key.Use = "sig"
+	key.Key.Kid = s.keyID
+	key.Key.K = base64.RawURLEncoding.EncodeToString(s.signingKey)
+	key.Key.Alg = "HS512"
+	key.ClientID = ClientID
+	key.KeyID = s.keyID
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_embedded": map[string]interface{}{
+			"clientKeys": []myfitnesspal.ClientKey{key},
+		},
+	})
+}
+
+// handleGetUser returns a user's identity profile
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.tokenUser(r)
+	if !ok || userID != r.PathValue("id") {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	s.mu.Lock()
+	user := *s.users[userID]
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, identityUser(user))
+}
+
+// identityUser returns a user as the identity API represents them
+func identityUser(user User) map[string]interface{} {
+	id, _ := strconv.ParseInt(user.ID, 10, 64)
+	return map[string]interface{}{
+		"userId": id,
+		"domain": "MFP",
+		"region": "US",
+		"status": "ACTIVE",
+		"profile": map[string]interface{}{
+			"fullName":    user.FirstName + " " + user.LastName,
+			"displayName": user.FirstName,
+			"firstName":   user.FirstName,
+			"lastName":    user.LastName,
+			"locale":      "en_US",
+		},
+		"profileEmails": map[string]interface{}{
+			"isEmailVerified": true,
+			"emails": []map[string]interface{}{
+				{"email": user.Email, "verified": true, "primary": true},
+			},
+		},
+		"accountLinks": []map[string]interface{}{
+			{"userId": id, "domain": "MFP", "domainUserId": user.DomainUserID},
+		},
+	}
+}
+
+// handleGetPreferences returns a user's default diary and location preferences
+func (s *Server) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok || user.DomainUserID != r.PathValue("id") {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"item": map[string]interface{}{
+			"id": user.DomainUserID,
+			"diary_preferences": map[string]interface{}{
+				"meal_names": user.MealNames,
+			},
+			"location_preferences": map[string]interface{}{
+				"time_zone": "UTC",
+			},
+		},
+	})
+}
+
+// handleCreateFood stores a new food
+func (s *Server) handleCreateFood(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	var body struct {
+		Item myfitnesspal.FoodItem `json:"item"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+
+	// Foods belong to the user who created them
+	body.Item.UserID = user.DomainUserID
+	s.mu.Lock()
+	food := s.addFood(body.Item)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": []myfitnesspal.FoodItem{food},
+	})
+}
+
+// addFood stores a food, assigning its ID and version. s.mu must be held.
+func (s *Server) addFood(food myfitnesspal.FoodItem) myfitnesspal.FoodItem {
+	food.ID = strconv.Itoa(s.newID())
+	food.Version = food.ID
+	s.foods[food.ID] = food
+	return food
+}
+
+// handleSearch searches food descriptions and brand names
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	scope := r.URL.Query().Get("scope")
+	maxItems, err := strconv.Atoi(r.URL.Query().Get("max_items"))
+	if err != nil || maxItems <= 0 {
+		maxItems = 25
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []map[string]interface{}{}
+	for id := 1; id <= s.nextID && len(items) < maxItems; id++ {
+		food, ok := s.foods[strconv.Itoa(id)]
+		if !ok {
+			continue
+		}
+		own := food.UserID == user.ID || food.UserID == user.DomainUserID
+		if scope == "user" && !own || !food.Public && !own {
+			continue
+		}
+		text := strings.ToLower(food.BrandName + " " + food.Description)
+		if !strings.Contains(text, query) {
+			continue
+		}
+		items = append(items, map[string]interface{}{
+			"item": food,
+			"type": "food",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+// handleGetDiary returns a user's diary items for a date
+func (s *Server) handleGetDiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	date := r.URL.Query().Get("entry_date")
+	types := map[string]bool{}
+	for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if t != "" {
+			types[t] = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []map[string]interface{}{}
+	for _, item := range s.diary {
+		if item.userID == user.DomainUserID && item.date == date && (len(types) == 0 || types[item.kind]) {
+			items = append(items, item.body)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+// replacedDiaryTypes are diary item types of which a user has one per day, or one per day and source
+var replacedDiaryTypes = map[string]bool{
+	"water_entry":     true,
+	"food_note":       true,
+	"exercise_note":   true,
+	"steps_aggregate": true,
+}
+
+// handleAddDiary stores diary items
+func (s *Server) handleAddDiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	var body struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var created []map[string]interface{}
+	for _, raw := range body.Items {
+		item, err := s.newDiaryItem(user, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if replacedDiaryTypes[item.kind] {
+			s.removeDiaryItems(func(existing diaryItem) bool {
+				return existing.userID == item.userID && existing.date == item.date &&
+					existing.kind == item.kind && existing.source == item.source
+			})
+		}
+		s.diary = append(s.diary, item)
+		created = append(created, item.body)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": created})
+}
+
+// newDiaryItem builds a diary item from a request. Food entries have their food and
+// nutritional contents filled in. s.mu must be held.
+func (s *Server) newDiaryItem(user User, raw json.RawMessage) (diaryItem, error) {
+	var header struct {
+		Type   string `json:"type"`
+		Date   string `json:"date"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return diaryItem{}, fmt.Errorf("invalid diary item")
+	}
+	if header.Type == "" || header.Date == "" {
+		return diaryItem{}, fmt.Errorf("diary item needs a type and date")
+	}
+
+	item := diaryItem{
+		id:     strconv.Itoa(s.newID()),
+		userID: user.DomainUserID,
+		date:   header.Date,
+		kind:   header.Type,
+		source: header.Source,
+	}
+
+	var body interface{} = json.RawMessage(raw)
+	if header.Type == "food_entry" {
+		var req myfitnesspal.FoodDiaryAddRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			return diaryItem{}, fmt.Errorf("invalid food entry: %v", err)
+		}
+		food, ok := s.foods[req.Food.ID]
+		if !ok {
+			return diaryItem{}, fmt.Errorf("unknown food: %s", req.Food.ID)
+		}
+
+		multiplier := req.ServingSize.NutritionMultiplier
+		if multiplier == 0 {
+			multiplier = 1
+		}
+		meals := myfitnesspal.MealConfig{Names: user.MealNames}
+		if err := meals.Validate(req.MealPosition); err != nil {
+			return diaryItem{}, err
+		}
+		meal := meals.Format(req.MealPosition)
+		entry := myfitnesspal.FoodEntry{
+			ID:                  item.id,
+			Type:                "food_entry",
+			Date:                req.Date,
+			MealName:            meal,
+			MealPosition:        req.MealPosition,
+			Food:                food,
+			ServingSize:         req.ServingSize,
+			Servings:            req.Servings,
+			NutritionalContents: food.NutritionalContents.Scale(req.Servings * multiplier),
+			ImageIDs:            req.ImageIDs,
+			Tags:                req.Tags,
+		}
+		if req.Geolocation != nil {
+			entry.Geolocation = *req.Geolocation
+		}
+		if req.ConsumedAt != nil {
+			consumedAt := req.ConsumedAt.Format(time.RFC3339)
+			entry.ConsumedAt = &consumedAt
+		}
+		body = entry
+	}
+
+	// Round trip through JSON to store a plain map with the ID set
+	data, err := json.Marshal(body)
+	if err != nil {
+		return diaryItem{}, err
+	}
+	if err := json.Unmarshal(data, &item.body); err != nil {
+		return diaryItem{}, err
+	}
+	item.body["id"] = item.id
+
+	return item, nil
+}
+
+// handleDeleteDiary deletes a diary item
+func (s *Server) handleDeleteDiary(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.apiUser(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid access token")
+		return
+	}
+
+	s.mu.Lock()
+	removed := s.removeDiaryItems(func(item diaryItem) bool {
+		return item.userID == user.DomainUserID && item.id == r.PathValue("id")
+	})
+	s.mu.Unlock()
+
+	if removed == 0 {
+		writeError(w, http.StatusNotFound, "diary entry not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeDiaryItems removes the diary items matching remove and returns how many there were. s.mu must be held.
+func (s *Server) removeDiaryItems(remove func(diaryItem) bool) int {
+	kept := s.diary[:0]
+	for _, item := range s.diary {
+		if !remove(item) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(s.diary) - len(kept)
+	s.diary = kept
+	return removed
+}
+
+// tokenUser returns the identity user ID of the request's bearer token, "" for client tokens
+func (s *Server) tokenUser(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userID, ok := s.tokens[token]
+	return userID, ok
+}
+
+// apiUser returns the user making an API request, checking the token matches the mfp-user-id header
+func (s *Server) apiUser(r *http.Request) (User, bool) {
+	userID, ok := s.tokenUser(r)
+	if !ok || userID == "" {
+		return User{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user := *s.users[userID]
+	if r.Header.Get("mfp-user-id") != user.DomainUserID {
+		return User{}, false
+	}
+	return user, true
+}
+
+// newID returns a new unique ID. s.mu must be held.
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the API's format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error":             http.StatusText(status),
+		"error_description": message,
+	})
+}
+
+// defaultMealNames returns the meal names new users start with
+func defaultMealNames() []string {
+	return []string{"Breakfast", "Lunch", "Dinner", "Snacks"}
+}
+
+// randomBytes returns n cryptographically random bytes
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,170 @@
+package mfptest
+
+import (
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+)
+
+// newTestClient starts a Server with one user and returns a client logged in as them
+func newTestClient(t *testing.T) (*Server, *myfitnesspal.Client, *myfitnesspal.UserSession) {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser("test@example.com", "password")
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, client, session
+}
+
+func TestServerLogin(t *testing.T) {
+	srv, client, session := newTestClient(t)
+
+	if session.AccessToken == "" || session.RefreshToken == "" || session.DomainUserID == "" {
+		t.Fatalf("Login returned an incomplete session: %+v", session)
+	}
+	if _, err := client.Login("test@example.com", "wrong"); err == nil {
+		t.Error("Login with the wrong password succeeded")
+	}
+
+	user, err := client.GetUser(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Profile.FirstName != "Test" {
+		t.Errorf("GetUser first name = %q, want %q", user.Profile.FirstName, "Test")
+	}
+	if len(srv.Requests()) == 0 {
+		t.Error("Requests() is empty after logging in")
+	}
+}
+
+func TestServerRefreshAndRevoke(t *testing.T) {
+	_, client, session := newTestClient(t)
+
+	refreshed, err := client.RefreshUserToken(session.UserID, session.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.AccessToken == session.AccessToken {
+		t.Error("RefreshUserToken returned the same access token")
+	}
+	if _, err := client.GetUser(refreshed); err != nil {
+		t.Errorf("GetUser with the refreshed session: %v", err)
+	}
+
+	refreshToken := refreshed.RefreshToken
+	if err := client.Logout(refreshed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RefreshUserToken(session.UserID, refreshToken); err == nil {
+		t.Error("RefreshUserToken with a revoked refresh token succeeded")
+	}
+}
+
+func TestServerSearchScopes(t *testing.T) {
+	srv, client, session := newTestClient(t)
+
+	srv.AddFood(myfitnesspal.FoodItem{Description: "Apple", Public: true})
+	srv.AddFood(myfitnesspal.FoodItem{Description: "Someone else's apple pie"})
+	if _, err := client.CreateFood(session, myfitnesspal.FoodItem{Description: "My apple crumble"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{"all", []string{"Apple", "My apple crumble"}},
+		{"user", []string{"My apple crumble"}},
+	}
+	for _, tt := range tests {
+		results, err := client.SearchFood(session, myfitnesspal.SearchFoodRequest{Query: "apple", Scope: &tt.scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Item.Description)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("scope %s found %v, want %v", tt.scope, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("scope %s found %v, want %v", tt.scope, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestServerDiary(t *testing.T) {
+	srv, client, session := newTestClient(t)
+
+	food := srv.AddFood(myfitnesspal.FoodItem{Description: "Apple", Public: true})
+	day := myfitnesspal.NewDate(2026, 3, 1)
+	req := myfitnesspal.FoodDiaryAddRequest{Type: "food_entry", Date: day, Servings: 1, MealPosition: myfitnesspal.Lunch}
+	req.Food.ID = food.ID
+	req.Food.Version = food.Version
+
+	resp, err := client.AddFoodToDiary(session, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].MealName != "Lunch" {
+		t.Fatalf("AddFoodToDiary = %+v, want one Lunch entry", resp.Items)
+	}
+	entry := resp.Items[0]
+
+	entries, err := client.GetFoodDiary(session, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Fatalf("GetFoodDiary = %+v, want the added entry", entries)
+	}
+	if entries, err := client.GetFoodDiary(session, day.AddDays(1)); err != nil || len(entries) != 0 {
+		t.Errorf("GetFoodDiary for the next day = %+v, %v, want no entries", entries, err)
+	}
+
+	if err := client.DeleteDiaryEntry(session, entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := client.GetFoodDiary(session, day); err != nil || len(entries) != 0 {
+		t.Errorf("GetFoodDiary after DeleteDiaryEntry = %+v, %v, want no entries", entries, err)
+	}
+}
+
+func TestServerNamesEntriesWithTheUsersMeals(t *testing.T) {
+	srv, client, session := newTestClient(t)
+	srv.SetMealNames(session.UserID, "Early", "Midday", "Evening", "Snacks", "Pre-workout")
+
+	meals, err := client.GetMealConfig(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, err := meals.Name(4); err != nil || name != "Pre-workout" {
+		t.Errorf("meal 4 = %q, %v, want Pre-workout", name, err)
+	}
+
+	food := srv.AddFood(myfitnesspal.FoodItem{Description: "Apple", Public: true})
+	req := myfitnesspal.FoodDiaryAddRequest{Type: "food_entry", Date: myfitnesspal.NewDate(2026, 3, 1), Servings: 1, MealPosition: 4}
+	req.Food.ID = food.ID
+	resp, err := client.AddFoodToDiary(session, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Items[0].MealName; got != "Pre-workout" {
+		t.Errorf("MealName = %q, want Pre-workout", got)
+	}
+}
@@ -0,0 +1,76 @@
+package myfitnesspal
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Middleware wraps the transport used for every request the client sends, including
+// the ones made while logging in. Use RequestInfoFrom on the request's context to
+// find out which operation and user a request belongs to.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper, for writing middleware
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// RequestInfo describes the client call an outgoing request was made for
+type RequestInfo struct {
+	// Operation is the name of the Client method that sent the request, e.g. "SearchFood"
+	Operation string
+	// DomainUserID is the session's domain user ID, or empty for requests made without a session
+	DomainUserID string
+	// Attempt is 1 for the first try of a request and counts up on retries, see WithRetry
+	Attempt int
+}
+
+// requestInfoKey is the context key for RequestInfo
+type requestInfoKey struct{}
+
+// RequestInfoFrom returns the RequestInfo of a request made by the client
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// WithMiddleware adds middleware to the client's transport.
+// The first middleware given is the outermost, so it sees each request first.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// setAttempt records a request's retry attempt in its RequestInfo. Resty runs it before every attempt.
+func setAttempt(_ *resty.Client, req *resty.Request) error {
+	if info, ok := RequestInfoFrom(req.Context()); ok {
+		info.Attempt = req.Attempt
+		req.SetContext(context.WithValue(req.Context(), requestInfoKey{}, info))
+	}
+	return nil
+}
+
+// roundTripper returns the transport with the client's middleware applied, or nil to use resty's default
+func (c *Client) roundTripper() http.RoundTripper {
+	if len(c.middleware) == 0 && c.logger == nil {
+		return c.transport
+	}
+
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if c.logger != nil {
+		transport = c.logRequests(transport)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+	return transport
+}
@@ -0,0 +1,52 @@
+package myfitnesspal_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/mfptest"
+)
+
+type ctxKey struct{}
+
+func TestWithContextReachesMiddleware(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var operations []string
+	client, err := srv.NewClient(myfitnesspal.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := myfitnesspal.RequestInfoFrom(req.Context())
+			if req.Context().Value(ctxKey{}) == "login" {
+				operations = append(operations, info.Operation)
+			}
+			return next.RoundTrip(req)
+		})
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "login")
+	if _, err := client.WithContext(ctx).Login("test@example.com", "password"); err != nil {
+		t.Fatal(err)
+	}
+	if len(operations) == 0 || operations[0] != "Login" {
+		t.Errorf("middleware saw the context on %v, want every Login request", operations)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.WithContext(cancelled).Login("test@example.com", "password"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Login with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	// The original client is unaffected
+	if _, err := client.Login("test@example.com", "password"); err != nil {
+		t.Errorf("Login after WithContext: %v", err)
+	}
+}
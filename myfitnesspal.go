@@ -1,7 +1,10 @@
 package myfitnesspal
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
 	"encoding/base64"
@@ -20,16 +23,45 @@ const (
 type Client struct {
 	identityClient *resty.Client
 	apiClient      *resty.Client
+	identityURL    string
+	apiURL         string
+	transport      http.RoundTripper
+	middleware     []Middleware
+	logger         *slog.Logger
+	tokenStore     TokenStore
+	retries        int
+	maxRetryWait   time.Duration
 	clientID       string
 	clientSecret   string
 	deviceID       string
 	clientToken    *TokenResponse
 	signingKey     []byte
 	keyID          string
+	ctx            context.Context
 }
 
-// setStandardHeaders sets the standard headers for API requests
-func (c *Client) setStandardHeaders(req *resty.Request, session *UserSession) {
+// WithContext returns a copy of the client that sends its requests with ctx, so they can be
+// cancelled and middleware can see values the caller put in it, such as a tracing span
+// covering a whole Login. The copy shares the original's connections and tokens.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// setStandardHeaders sets the standard headers for API requests and records
+// the operation and session on the request's context for middleware
+func (c *Client) setStandardHeaders(req *resty.Request, operation string, session *UserSession) {
+	info := RequestInfo{Operation: operation}
+	if session != nil {
+		info.DomainUserID = session.DomainUserID
+	}
+	ctx := req.Context()
+	if c.ctx != nil {
+		ctx = c.ctx
+	}
+	req.SetContext(context.WithValue(ctx, requestInfoKey{}, info))
+
 	req.SetHeader("Accept", "application/json")
 	req.SetHeader("Content-Type", "application/json")
 	req.SetHeader("user-agent", userAgent)
@@ -46,13 +78,50 @@ func (c *Client) setStandardHeaders(req *resty.Request, session *UserSession) {
 	}
 }
 
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURLs points the client at different identity and API servers, such as a mfptest.Server
+func WithBaseURLs(identityURL, apiURL string) Option {
+	return func(c *Client) {
+		c.identityURL = identityURL
+		c.apiURL = apiURL
+	}
+}
+
+// WithTransport sends all requests through the given transport instead of http.DefaultTransport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// newRestyClient creates a resty client for one of the MyFitnessPal servers
+func (c *Client) newRestyClient(baseURL string) *resty.Client {
+	client := resty.New()
+	client.SetBaseURL(baseURL)
+	client.OnBeforeRequest(setAttempt)
+	c.configureRetries(client)
+	if transport := c.roundTripper(); transport != nil {
+		client.SetTransport(transport)
+	}
+	return client
+}
+
 // NewClient creates a new MyFitnessPal API client
-func NewClient(clientID, clientSecret string) (*Client, error) {
-	identityClient := resty.New()
-	identityClient.SetBaseURL(identityBaseURL)
+func NewClient(clientID, clientSecret string, opts ...Option) (*Client, error) {
+	client := &Client{
+		identityURL:  identityBaseURL,
+		apiURL:       apiBaseURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
 
-	apiClient := resty.New()
-	apiClient.SetBaseURL(apiBaseURL)
+	identityClient := client.newRestyClient(client.identityURL)
+	apiClient := client.newRestyClient(client.apiURL)
 
 	// Generate a random device ID
 	deviceID := fmt.Sprintf("%x-%x-%x-%x-%x",
@@ -62,13 +131,9 @@ func NewClient(clientID, clientSecret string) (*Client, error) {
 		time.Now().UnixNano()>>8,
 		time.Now().UnixNano())
 
-	client := &Client{
-		identityClient: identityClient,
-		apiClient:      apiClient,
-		clientID:       clientID,
-		clientSecret:   clientSecret,
-		deviceID:       deviceID,
-	}
+	client.identityClient = identityClient
+	client.apiClient = apiClient
+	client.deviceID = deviceID
 
 	// Get client credentials token
 	clientToken, err := client.GetClientCredentialsToken()
@@ -0,0 +1,100 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NoteType is the section of the diary a note belongs to
+type NoteType string
+
+const (
+	FoodNote     NoteType = "food_note"
+	ExerciseNote NoteType = "exercise_note"
+)
+
+// DiaryNote represents a note on a diary day
+type DiaryNote struct {
+	Type NoteType `json:"type"`
+	Date Date     `json:"date"`
+	Body string   `json:"body"`
+}
+
+// GetDiaryNotes fetches the food and exercise notes for a date.
+// Days without notes return an empty slice.
+func (c *Client) GetDiaryNotes(session *UserSession, date Date) ([]DiaryNote, error) {
+	items, err := c.getDiaryItems(session, "GetDiaryNotes", date, string(FoodNote), string(ExerciseNote))
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]DiaryNote, 0, len(items))
+	for _, item := range items {
+		var note DiaryNote
+		if err := json.Unmarshal(item, &note); err != nil {
+			return nil, fmt.Errorf("failed to parse diary note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// GetDiaryNote fetches a date's note of the given type. It returns an empty note if there is none.
+func (c *Client) GetDiaryNote(session *UserSession, date Date, noteType NoteType) (*DiaryNote, error) {
+	notes, err := c.GetDiaryNotes(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range notes {
+		if notes[i].Type == noteType {
+			return &notes[i], nil
+		}
+	}
+	return &DiaryNote{Type: noteType, Date: date}, nil
+}
+
+// GetDiaryNotesRange fetches the food and exercise notes from start to end inclusive, in date order
+func (c *Client) GetDiaryNotesRange(session *UserSession, start, end Date) ([]DiaryNote, error) {
+	var notes []DiaryNote
+	for date := range DateRange(start, end) {
+		dayNotes, err := c.GetDiaryNotes(session, date)
+		if err != nil {
+			return nil, fmt.Errorf("error getting notes for %s: %w", date, err)
+		}
+		notes = append(notes, dayNotes...)
+	}
+	return notes, nil
+}
+
+// SetDiaryNote sets a date's food or exercise note, replacing any existing note of that type.
+// An empty body clears the note.
+func (c *Client) SetDiaryNote(session *UserSession, note DiaryNote) error {
+	if note.Date.IsZero() {
+		return fmt.Errorf("no diary date provided")
+	}
+	if note.Type != FoodNote && note.Type != ExerciseNote {
+		return fmt.Errorf("invalid note type: %s. Must be 'food_note' or 'exercise_note'", note.Type)
+	}
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"items": []DiaryNote{note},
+		})
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "SetDiaryNote", session)
+
+	resp, err := req.Post("/v2/diary")
+	if err != nil {
+		return fmt.Errorf("failed to set diary note: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return fmt.Errorf("set diary note failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package otelmfp instruments myfitnesspal.Client with OpenTelemetry tracing and metrics.
+// It is a separate package so the client itself does not depend on OpenTelemetry.
+//
+//	client, err := myfitnesspal.NewClient(clientID, clientSecret,
+//		myfitnesspal.WithMiddleware(otelmfp.Middleware()))
+//
+// Every request gets a client span named after the client method that sent it, such as
+// "mfp.SearchFood" or "mfp.Login", and the trace context is propagated in its headers.
+// Client methods don't take a context, so by default each request's span is a root span,
+// and the several requests a Login or token refresh makes show up as separate traces.
+// To group them, start a span for the operation and call the client through
+// Client.WithContext; request spans become its children:
+//
+//	ctx, span := tracer.Start(ctx, "login")
+//	session, err := client.WithContext(ctx).Login(username, password)
+//	span.End()
+//
+// Metrics recorded:
+//
+//	mfp.client.requests           Requests sent, by operation, method and status
+//	mfp.client.request.duration   Request latency in seconds, by operation, method and status
+//	mfp.client.retries            Retried requests, by operation
+//	mfp.client.token_refreshes    Refresh token exchanges, by outcome
+//	mfp.client.rate_limited       Requests rejected with 429 Too Many Requests, by operation
+//	mfp.client.rate_limit.wait    Time the server asked the client to wait before retrying, in seconds
+//
+// The client only retries requests, and waits out rate limits, when it is created with
+// myfitnesspal.WithRetry. Without it mfp.client.retries stays at zero, and rate limited
+// requests fail instead of waiting the time recorded in mfp.client.rate_limit.wait.
+package otelmfp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/seonixx/myfitnesspal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package to tracer and meter providers
+const instrumentationName = "github.com/seonixx/myfitnesspal/otelmfp"
+
+// refreshOperation is the client method that exchanges refresh tokens
+const refreshOperation = "RefreshUserToken"
+
+// config holds the providers used by the middleware
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to otel.GetTracerProvider().
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. Defaults to otel.GetMeterProvider().
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagators sets the propagators used to inject trace context into request headers.
+// Defaults to otel.GetTextMapPropagator().
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// instruments are the metrics recorded by the middleware
+type instruments struct {
+	requests       metric.Int64Counter
+	duration       metric.Float64Histogram
+	retries        metric.Int64Counter
+	tokenRefreshes metric.Int64Counter
+	rateLimits     metric.Int64Counter
+	rateLimitWait  metric.Float64Histogram
+}
+
+// newInstruments creates the metrics. If the meter rejects any of them, the error is
+// reported to otel.Handle and no metrics are recorded.
+func newInstruments(meter metric.Meter) instruments {
+	var inst instruments
+	var err, errs error
+
+	inst.requests, err = meter.Int64Counter("mfp.client.requests",
+		metric.WithDescription("Requests sent to MyFitnessPal"),
+		metric.WithUnit("{request}"))
+	errs = errors.Join(errs, err)
+	inst.duration, err = meter.Float64Histogram("mfp.client.request.duration",
+		metric.WithDescription("Latency of requests to MyFitnessPal"),
+		metric.WithUnit("s"))
+	errs = errors.Join(errs, err)
+	inst.retries, err = meter.Int64Counter("mfp.client.retries",
+		metric.WithDescription("Requests to MyFitnessPal that were retries of an earlier attempt"),
+		metric.WithUnit("{request}"))
+	errs = errors.Join(errs, err)
+	inst.tokenRefreshes, err = meter.Int64Counter("mfp.client.token_refreshes",
+		metric.WithDescription("Refresh token exchanges"),
+		metric.WithUnit("{refresh}"))
+	errs = errors.Join(errs, err)
+	inst.rateLimits, err = meter.Int64Counter("mfp.client.rate_limited",
+		metric.WithDescription("Requests rejected by MyFitnessPal's rate limit"),
+		metric.WithUnit("{request}"))
+	errs = errors.Join(errs, err)
+	inst.rateLimitWait, err = meter.Float64Histogram("mfp.client.rate_limit.wait",
+		metric.WithDescription("Time MyFitnessPal asked the client to wait after a rate limited request"),
+		metric.WithUnit("s"))
+	errs = errors.Join(errs, err)
+
+	if errs != nil {
+		otel.Handle(errs)
+		return newInstruments(noop.Meter{})
+	}
+	return inst
+}
+
+// Middleware returns client middleware that traces requests and records metrics.
+// Request spans are children of any span in the context given to Client.WithContext.
+func Middleware(opts ...Option) myfitnesspal.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	inst := newInstruments(cfg.meterProvider.Meter(instrumentationName))
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := myfitnesspal.RequestInfoFrom(req.Context())
+			operation := info.Operation
+			if operation == "" {
+				operation = "unknown"
+			}
+
+			ctx, span := tracer.Start(req.Context(), "mfp."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("mfp.operation", operation),
+					attribute.String("http.request.method", req.Method),
+					attribute.String("server.address", req.URL.Hostname()),
+					attribute.String("url.path", req.URL.Path),
+				))
+			defer span.End()
+			if info.Attempt > 1 {
+				span.SetAttributes(attribute.Int("http.request.resend_count", info.Attempt-1))
+				inst.retries.Add(ctx, 1, metric.WithAttributes(attribute.String("mfp.operation", operation)))
+			}
+
+			// Requests must not be modified by a RoundTripper, so inject headers into a copy
+			req = req.Clone(ctx)
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start).Seconds()
+
+			attrs := []attribute.KeyValue{
+				attribute.String("mfp.operation", operation),
+				attribute.String("http.request.method", req.Method),
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				attrs = append(attrs, attribute.String("error.type", "transport"))
+			} else {
+				span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+				attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+				if resp.StatusCode >= 400 {
+					span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+					attrs = append(attrs, attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+				}
+			}
+
+			inst.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+			inst.duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+
+			if operation == refreshOperation {
+				outcome := "success"
+				if err != nil || resp.StatusCode != http.StatusOK {
+					outcome = "failure"
+				}
+				inst.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+			}
+
+			if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+				opAttr := metric.WithAttributes(attribute.String("mfp.operation", operation))
+				inst.rateLimits.Add(ctx, 1, opAttr)
+				if wait, ok := myfitnesspal.RetryAfter(resp.Header); ok {
+					inst.rateLimitWait.Record(ctx, wait.Seconds(), opAttr)
+					span.AddEvent("rate limited", trace.WithAttributes(attribute.Float64("mfp.retry_after", wait.Seconds())))
+				}
+			}
+
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package otelmfp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/mfptest"
+	"github.com/seonixx/myfitnesspal/otelmfp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestSpansJoinTheCallersTrace(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var traceparents []string
+	capture := func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if info, _ := myfitnesspal.RequestInfoFrom(req.Context()); info.Operation == "Login" {
+				traceparents = append(traceparents, req.Header.Get("traceparent"))
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	client, err := srv.NewClient(myfitnesspal.WithMiddleware(
+		otelmfp.Middleware(otelmfp.WithPropagators(propagation.TraceContext{})),
+		capture,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+
+	if _, err := client.WithContext(ctx).Login("test@example.com", "password"); err != nil {
+		t.Fatal(err)
+	}
+	if len(traceparents) == 0 {
+		t.Fatal("no Login requests seen")
+	}
+	for _, header := range traceparents {
+		if !strings.Contains(header, traceID.String()) {
+			t.Errorf("traceparent %q is not in trace %s", header, traceID)
+		}
+	}
+}
+
+// countingProvider is a MeterProvider whose counters keep their totals, by instrument name
+type countingProvider struct {
+	noop.MeterProvider
+	totals map[string]*int64
+}
+
+func (p countingProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return countingMeter{totals: p.totals}
+}
+
+type countingMeter struct {
+	noop.Meter
+	totals map[string]*int64
+}
+
+func (m countingMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	total := new(int64)
+	m.totals[name] = total
+	return countingCounter{total: total}, nil
+}
+
+type countingCounter struct {
+	noop.Int64Counter
+	total *int64
+}
+
+func (c countingCounter) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	*c.total += incr
+}
+
+func TestRetriesAndRateLimitsAreCounted(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	// Rate limit the first attempt at GetMealConfig
+	limited := false
+	rateLimit := func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if info, _ := myfitnesspal.RequestInfoFrom(req.Context()); info.Operation != "GetMealConfig" || limited {
+				return next.RoundTrip(req)
+			}
+			limited = true
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": {"0"}},
+				Body:       io.NopCloser(strings.NewReader("{}")),
+				Request:    req,
+			}, nil
+		})
+	}
+
+	provider := countingProvider{totals: map[string]*int64{}}
+	client, err := srv.NewClient(
+		myfitnesspal.WithRetry(1, 10*time.Millisecond),
+		myfitnesspal.WithMiddleware(otelmfp.Middleware(otelmfp.WithMeterProvider(provider)), rateLimit),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetMealConfig(session); err != nil {
+		t.Fatalf("GetMealConfig after a retried 429: %v", err)
+	}
+
+	for name, want := range map[string]int64{"mfp.client.retries": 1, "mfp.client.rate_limited": 1} {
+		if got := *provider.totals[name]; got != want {
+			t.Errorf("%s = %d, want %d", name, got, want)
+		}
+	}
+}
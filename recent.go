@@ -0,0 +1,79 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// LoggedFood represents a food the user has logged before, with the serving they last used
+type LoggedFood struct {
+	Food         FoodItem    `json:"food"`
+	ServingSize  ServingSize `json:"serving_size"`
+	Servings     float64     `json:"servings"`
+	MealPosition MealNumber  `json:"meal_position"`
+	LastUsed     Date        `json:"last_used"`
+	Count        int         `json:"count"` // Number of times logged, only set for frequent foods
+}
+
+// DiaryAddRequest returns a request to log the food again with the same serving
+func (f LoggedFood) DiaryAddRequest(date Date, meal MealNumber) FoodDiaryAddRequest {
+	req := FoodDiaryAddRequest{
+		Type:         "food_entry",
+		Date:         date,
+		MealPosition: meal,
+		Servings:     f.Servings,
+		ServingSize:  f.ServingSize,
+	}
+	req.Food.ID = f.Food.ID
+	req.Food.Version = f.Food.Version
+	return req
+}
+
+// RecentFoods fetches the foods the user most recently logged to a meal, newest first
+func (c *Client) RecentFoods(session *UserSession, meal MealNumber) ([]LoggedFood, error) {
+	if !meal.Valid() {
+		return nil, fmt.Errorf("invalid meal position: %d", int(meal))
+	}
+
+	return c.getLoggedFoods(session, "RecentFoods", "/v2/foods/recent", map[string]string{
+		"meal_position": strconv.Itoa(int(meal)),
+		"max_items":     "50",
+	})
+}
+
+// FrequentFoods fetches the foods the user logs most often, most frequent first
+func (c *Client) FrequentFoods(session *UserSession) ([]LoggedFood, error) {
+	return c.getLoggedFoods(session, "FrequentFoods", "/v2/foods/frequent", map[string]string{
+		"max_items": "50",
+	})
+}
+
+// getLoggedFoods fetches a list of previously logged foods
+func (c *Client) getLoggedFoods(session *UserSession, operation, path string, query map[string]string) ([]LoggedFood, error) {
+	req := c.apiClient.R().
+		SetQueryParams(query)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, operation, session)
+
+	resp, err := req.Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logged foods: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("get logged foods failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Items []LoggedFood `json:"items"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse logged foods response: %w", err)
+	}
+
+	return result.Items, nil
+}
@@ -0,0 +1,278 @@
+package myfitnesspal
+
+import (
+	"fmt"
+	"math"
+)
+
+// Nutrient is a nutrient that can be reported on
+type Nutrient string
+
+const (
+	NutrientCalories           Nutrient = "calories"
+	NutrientCarbohydrates      Nutrient = "carbohydrates"
+	NutrientProtein            Nutrient = "protein"
+	NutrientFat                Nutrient = "fat"
+	NutrientSaturatedFat       Nutrient = "saturated_fat"
+	NutrientPolyunsaturatedFat Nutrient = "polyunsaturated_fat"
+	NutrientMonounsaturatedFat Nutrient = "monounsaturated_fat"
+	NutrientTransFat           Nutrient = "trans_fat"
+	NutrientCholesterol        Nutrient = "cholesterol"
+	NutrientSodium             Nutrient = "sodium"
+	NutrientPotassium          Nutrient = "potassium"
+	NutrientFiber              Nutrient = "fiber"
+	NutrientSugar              Nutrient = "sugar"
+	NutrientVitaminA           Nutrient = "vitamin_a"
+	NutrientVitaminC           Nutrient = "vitamin_c"
+	NutrientCalcium            Nutrient = "calcium"
+	NutrientIron               Nutrient = "iron"
+)
+
+// Value returns the amount of the nutrient in the contents, with energy in calories
+func (n Nutrient) Value(contents NutritionalContents) (float64, error) {
+	switch n {
+	case NutrientCalories:
+		return contents.Energy.Calories(), nil
+	case NutrientCarbohydrates:
+		return contents.Carbohydrates, nil
+	case NutrientProtein:
+		return contents.Protein, nil
+	case NutrientFat:
+		return contents.Fat, nil
+	case NutrientSaturatedFat:
+		return contents.SaturatedFat, nil
+	case NutrientPolyunsaturatedFat:
+		return contents.PolyunsaturatedFat, nil
+	case NutrientMonounsaturatedFat:
+		return contents.MonounsaturatedFat, nil
+	case NutrientTransFat:
+		return contents.TransFat, nil
+	case NutrientCholesterol:
+		return contents.Cholesterol, nil
+	case NutrientSodium:
+		return contents.Sodium, nil
+	case NutrientPotassium:
+		return contents.Potassium, nil
+	case NutrientFiber:
+		return contents.Fiber, nil
+	case NutrientSugar:
+		return contents.Sugar, nil
+	case NutrientVitaminA:
+		return contents.VitaminA, nil
+	case NutrientVitaminC:
+		return contents.VitaminC, nil
+	case NutrientCalcium:
+		return contents.Calcium, nil
+	case NutrientIron:
+		return contents.Iron, nil
+	}
+	return 0, fmt.Errorf("invalid nutrient: %s", n)
+}
+
+// ReportPeriod is how days are grouped in a report
+type ReportPeriod string
+
+const (
+	ByDay   ReportPeriod = "day"
+	ByWeek  ReportPeriod = "week" // Weeks start on Monday
+	ByMonth ReportPeriod = "month"
+)
+
+// IncompleteDays is how a report treats days the user did not fully log
+type IncompleteDays int
+
+const (
+	// SkipEmptyDays leaves out days with no food entries
+	SkipEmptyDays IncompleteDays = iota
+	// SkipIncompleteDays leaves out days with fewer calories than ReportOptions.MinCalories
+	SkipIncompleteDays
+	// IncludeAllDays counts every day, treating empty days as zero
+	IncludeAllDays
+)
+
+// ReportOptions configures a nutrient report
+type ReportOptions struct {
+	Nutrients      []Nutrient // Defaults to calories, carbohydrates, protein and fat
+	Period         ReportPeriod
+	IncompleteDays IncompleteDays
+	MinCalories    float64 // Only used with SkipIncompleteDays
+}
+
+// ReportDay is the diary data for one day of a report
+type ReportDay struct {
+	Date    Date
+	Entries []FoodEntry
+	Goal    NutrientGoal
+}
+
+// NutrientStats summarizes one nutrient over a report period
+type NutrientStats struct {
+	Total        float64
+	Average      float64 // Per included day
+	Min          float64
+	Max          float64
+	DaysOverGoal int // Days over a goal of more than zero
+}
+
+// ReportGroup summarizes the days in one day, week or month of a report
+type ReportGroup struct {
+	Start       Date
+	End         Date
+	Days        int // Days included in the stats
+	SkippedDays int // Days left out as incomplete
+	Nutrients   map[Nutrient]NutrientStats
+}
+
+// NutrientReport summarizes nutrients over a date range
+type NutrientReport struct {
+	Start   Date
+	End     Date
+	Period  ReportPeriod
+	Groups  []ReportGroup
+	Overall ReportGroup
+}
+
+// defaultReportNutrients are reported when no nutrients are chosen
+var defaultReportNutrients = []Nutrient{NutrientCalories, NutrientCarbohydrates, NutrientProtein, NutrientFat}
+
+// GetNutrientReport reads the user's diary and goals from start to end inclusive and reports on them
+func (c *Client) GetNutrientReport(session *UserSession, start, end Date, opts ReportOptions) (*NutrientReport, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("report end %s is before start %s", end, start)
+	}
+
+	var days []ReportDay
+	var goals *NutritionGoals
+	for date := range DateRange(start, end) {
+		entries, err := c.GetFoodDiary(session, date)
+		if err != nil {
+			return nil, fmt.Errorf("error getting diary for %s: %w", date, err)
+		}
+		days = append(days, ReportDay{Date: date, Entries: entries})
+	}
+
+	// Goals apply from their ValidFrom date until the next change, so walking back from the
+	// end only needs a new fetch for days before the goals currently in hand took effect
+	for i := len(days) - 1; i >= 0; i-- {
+		date := days[i].Date
+		if goals == nil || date.Before(goals.ValidFrom) {
+			var err error
+			if goals, err = c.GetNutritionGoals(session, date); err != nil {
+				return nil, fmt.Errorf("error getting goals for %s: %w", date, err)
+			}
+		}
+		days[i].Goal = goals.ForDate(date)
+	}
+
+	return BuildNutrientReport(days, opts)
+}
+
+// BuildNutrientReport reports on days of diary data. Days must be in date order.
+func BuildNutrientReport(days []ReportDay, opts ReportOptions) (*NutrientReport, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no days to report on")
+	}
+	if opts.Period == "" {
+		opts.Period = ByDay
+	}
+	if opts.Period != ByDay && opts.Period != ByWeek && opts.Period != ByMonth {
+		return nil, fmt.Errorf("invalid report period: %s. Must be 'day', 'week' or 'month'", opts.Period)
+	}
+	if len(opts.Nutrients) == 0 {
+		opts.Nutrients = defaultReportNutrients
+	}
+	for _, nutrient := range opts.Nutrients {
+		if _, err := nutrient.Value(NutritionalContents{}); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &NutrientReport{
+		Start:  days[0].Date,
+		End:    days[len(days)-1].Date,
+		Period: opts.Period,
+	}
+
+	var group []ReportDay
+	for i, day := range days {
+		group = append(group, day)
+		if i == len(days)-1 || periodStart(days[i+1].Date, opts.Period) != periodStart(day.Date, opts.Period) {
+			report.Groups = append(report.Groups, summarizeDays(group, opts))
+			group = nil
+		}
+	}
+	report.Overall = summarizeDays(days, opts)
+
+	return report, nil
+}
+
+// periodStart returns the first date of the period containing date
+func periodStart(date Date, period ReportPeriod) Date {
+	switch period {
+	case ByWeek:
+		// Days since Monday
+		return date.AddDays(-((int(date.Weekday()) + 6) % 7))
+	case ByMonth:
+		return NewDate(date.Year, date.Month, 1)
+	}
+	return date
+}
+
+// summarizeDays computes nutrient stats over a group of days
+func summarizeDays(days []ReportDay, opts ReportOptions) ReportGroup {
+	group := ReportGroup{
+		Start:     days[0].Date,
+		End:       days[len(days)-1].Date,
+		Nutrients: map[Nutrient]NutrientStats{},
+	}
+
+	for _, nutrient := range opts.Nutrients {
+		group.Nutrients[nutrient] = NutrientStats{Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+
+	for _, day := range days {
+		totals := DiaryTotals(day.Entries)
+		if !includeDay(day, totals, opts) {
+			group.SkippedDays++
+			continue
+		}
+		group.Days++
+
+		goal := day.Goal.Contents()
+		for _, nutrient := range opts.Nutrients {
+			value, _ := nutrient.Value(totals)
+			target, _ := nutrient.Value(goal)
+
+			stats := group.Nutrients[nutrient]
+			stats.Total += value
+			stats.Min = math.Min(stats.Min, value)
+			stats.Max = math.Max(stats.Max, value)
+			if target > 0 && value > target {
+				stats.DaysOverGoal++
+			}
+			group.Nutrients[nutrient] = stats
+		}
+	}
+
+	for nutrient, stats := range group.Nutrients {
+		if group.Days == 0 {
+			stats.Min, stats.Max = 0, 0
+		} else {
+			stats.Average = stats.Total / float64(group.Days)
+		}
+		group.Nutrients[nutrient] = stats
+	}
+
+	return group
+}
+
+// includeDay reports whether a day counts towards a report
+func includeDay(day ReportDay, totals NutritionalContents, opts ReportOptions) bool {
+	switch opts.IncompleteDays {
+	case IncludeAllDays:
+		return true
+	case SkipIncompleteDays:
+		return len(day.Entries) > 0 && totals.Energy.Calories() >= opts.MinCalories
+	}
+	return len(day.Entries) > 0
+}
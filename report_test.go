@@ -0,0 +1,103 @@
+package myfitnesspal_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/mfptest"
+)
+
+// goalsServer answers nutrient goal requests in place of the API, with goals that changed
+// on changedOn, and counts the requests
+func goalsServer(t *testing.T, changedOn myfitnesspal.Date, requests *int) myfitnesspal.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/v2/nutrient-goals" {
+				return next.RoundTrip(req)
+			}
+			*requests++
+
+			date, err := myfitnesspal.ParseDate(req.URL.Query().Get("date"))
+			if err != nil {
+				t.Fatalf("invalid goals date: %v", err)
+			}
+			goals := myfitnesspal.NutritionGoals{ValidFrom: changedOn}
+			goals.DefaultGoal.Energy.Value = 2000
+			if date.Before(changedOn) {
+				goals.ValidFrom = changedOn.AddDays(-365)
+				goals.DefaultGoal.Energy.Value = 2500
+			}
+
+			body, _ := json.Marshal(map[string]interface{}{"items": []myfitnesspal.NutritionGoals{goals}})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(string(body))),
+				Request:    req,
+			}, nil
+		})
+	}
+}
+
+func TestGetNutrientReportFetchesGoalsOncePerChange(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	start := myfitnesspal.NewDate(2026, 1, 1)
+	end := start.AddDays(89)
+	changedOn := start.AddDays(30)
+
+	var goalRequests int
+	client, err := srv.NewClient(myfitnesspal.WithMiddleware(goalsServer(t, changedOn, &goalRequests)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2200 calories is over the new goal of 2000 but under the old goal of 2500
+	food := srv.AddFood(myfitnesspal.FoodItem{
+		Description:         "Big meal",
+		Public:              true,
+		NutritionalContents: myfitnesspal.NutritionalContents{Energy: myfitnesspal.Energy{Unit: "calories", Value: 2200}},
+	})
+	for date := range myfitnesspal.DateRange(start, end) {
+		req := myfitnesspal.FoodDiaryAddRequest{Type: "food_entry", Date: date, Servings: 1, ServingSize: myfitnesspal.ServingSize{Value: 1, Unit: "meal", NutritionMultiplier: 1}}
+		req.Food.ID = food.ID
+		req.Food.Version = food.Version
+		if _, err := client.AddFoodToDiary(session, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := client.GetNutrientReport(session, start, end, myfitnesspal.ReportOptions{
+		Nutrients:      []myfitnesspal.Nutrient{myfitnesspal.NutrientCalories},
+		IncompleteDays: myfitnesspal.IncludeAllDays,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if goalRequests != 2 {
+		t.Errorf("fetched goals %d times, want 2", goalRequests)
+	}
+	if len(report.Groups) != 90 {
+		t.Fatalf("got %d days, want 90", len(report.Groups))
+	}
+	for _, day := range report.Groups {
+		want := 1
+		if day.Start.Before(changedOn) {
+			want = 0
+		}
+		if got := day.Nutrients[myfitnesspal.NutrientCalories].DaysOverGoal; got != want {
+			t.Errorf("%s: %d days over goal, want %d", day.Start, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package myfitnesspal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// retryWaitTime is the shortest wait before a retry, doubling with each further attempt
+// unless the server says how long to wait
+const retryWaitTime = 500 * time.Millisecond
+
+// WithRetry retries requests up to retries times. Requests rejected with 429 Too Many Requests
+// are always retried, after the wait the server's Retry-After header asks for. GET, PUT and
+// DELETE requests are also retried when they fail to send or get a 502, 503 or 504 response,
+// with exponential backoff. No wait is longer than maxWait.
+// Without this option requests are never retried.
+func WithRetry(retries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.maxRetryWait = maxWait
+	}
+}
+
+// configureRetries applies the client's retry policy to a resty client
+func (c *Client) configureRetries(client *resty.Client) {
+	if c.retries <= 0 {
+		return
+	}
+	// Resty logs every failed attempt to stderr, including Login's deliberate stop at a redirect.
+	// WithLogger covers request logging instead.
+	client.SetLogger(quietLogger{})
+	client.SetRetryCount(c.retries)
+	client.SetRetryWaitTime(min(retryWaitTime, c.maxRetryWait))
+	client.SetRetryMaxWaitTime(c.maxRetryWait)
+	client.AddRetryCondition(shouldRetry)
+	client.SetRetryAfter(func(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+		// Zero falls back to exponential backoff
+		wait, _ := RetryAfter(resp.Header())
+		return wait, nil
+	})
+}
+
+// quietLogger discards resty's log messages
+type quietLogger struct{}
+
+func (quietLogger) Errorf(string, ...interface{}) {}
+func (quietLogger) Warnf(string, ...interface{})  {}
+func (quietLogger) Debugf(string, ...interface{}) {}
+
+// shouldRetry decides whether a failed attempt is worth repeating
+func shouldRetry(resp *resty.Response, err error) bool {
+	if err != nil {
+		// Login stops at a redirect on purpose, and cancelled requests must not be resent
+		if errors.Is(err, resty.ErrAutoRedirectDisabled) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return resp != nil && idempotent(resp.Request.Method)
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests:
+		// The request was rejected before it was processed, so resending it is always safe
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(resp.Request.Method)
+	}
+	return false
+}
+
+// idempotent reports whether sending a request twice has the same effect as sending it once
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func RetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
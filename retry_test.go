@@ -0,0 +1,146 @@
+package myfitnesspal_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/mfptest"
+)
+
+// failFirst answers the first n requests for operation with status instead of sending them,
+// and records the attempt number of every request for it
+func failFirst(operation string, n, status int, attempts *[]int) myfitnesspal.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return myfitnesspal.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			info, _ := myfitnesspal.RequestInfoFrom(req.Context())
+			if info.Operation != operation {
+				return next.RoundTrip(req)
+			}
+			*attempts = append(*attempts, info.Attempt)
+			if len(*attempts) > n {
+				return next.RoundTrip(req)
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Retry-After": {"0"}},
+				Body:       io.NopCloser(strings.NewReader(`{"error":"try again"}`)),
+				Request:    req,
+			}, nil
+		})
+	}
+}
+
+func TestWithRetryResendsRateLimitedRequests(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var attempts []int
+	client, err := srv.NewClient(
+		myfitnesspal.WithRetry(2, 10*time.Millisecond),
+		myfitnesspal.WithMiddleware(failFirst("GetUser", 2, http.StatusTooManyRequests, &attempts)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attempts = nil
+
+	if _, err := client.GetUser(session); err != nil {
+		t.Fatalf("GetUser after two 429s: %v", err)
+	}
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[2] != 3 {
+		t.Errorf("attempts = %v, want [1 2 3]", attempts)
+	}
+}
+
+func TestWithRetryDoesNotResendUnsafeRequests(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var attempts []int
+	client, err := srv.NewClient(
+		myfitnesspal.WithRetry(2, 10*time.Millisecond),
+		myfitnesspal.WithMiddleware(failFirst("CreateFood", 1, http.StatusServiceUnavailable, &attempts)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateFood(session, myfitnesspal.FoodItem{Description: "Apple"}); err == nil {
+		t.Error("CreateFood succeeded after a 503")
+	}
+	if len(attempts) != 1 {
+		t.Errorf("CreateFood was sent %d times, want 1", len(attempts))
+	}
+}
+
+func TestWithoutRetryRequestsAreSentOnce(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var attempts []int
+	client, err := srv.NewClient(myfitnesspal.WithMiddleware(failFirst("GetMealConfig", 1, http.StatusTooManyRequests, &attempts)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetMealConfig(session); err == nil {
+		t.Error("GetMealConfig succeeded after a 429 without retries")
+	}
+	if len(attempts) != 1 || attempts[0] != 1 {
+		t.Errorf("attempts = %v, want [1]", attempts)
+	}
+}
+
+func TestLoggerRecordsRetryAttempts(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var attempts []int
+	client, err := srv.NewClient(
+		myfitnesspal.WithRetry(1, 10*time.Millisecond),
+		myfitnesspal.WithLogger(logger),
+		myfitnesspal.WithMiddleware(failFirst("GetMealConfig", 1, http.StatusTooManyRequests, &attempts)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Login("test@example.com", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(logs.String(), "attempt=") {
+		t.Errorf("first attempts were logged with an attempt number:\n%s", logs.String())
+	}
+	logs.Reset()
+
+	// The middleware answers the first attempt before it reaches the logger, so only the retry is logged
+	if _, err := client.GetMealConfig(session); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "operation=GetMealConfig") || !strings.Contains(logs.String(), "attempt=2") {
+		t.Errorf("want the retry logged with attempt=2, got:\n%s", logs.String())
+	}
+}
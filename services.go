@@ -0,0 +1,37 @@
+package myfitnesspal
+
+// Authenticator logs users in and out and refreshes their sessions
+type Authenticator interface {
+	Login(username, password string) (*UserSession, error)
+	RefreshUserToken(mfpUserID string, refreshToken string) (*UserSession, error)
+	Logout(session *UserSession) error
+}
+
+// UserService reads and updates user profiles
+type UserService interface {
+	GetUser(session *UserSession) (*User, error)
+	UpdateProfile(session *UserSession, patch ProfilePatch) (*User, error)
+}
+
+// FoodService creates and finds foods
+type FoodService interface {
+	CreateFood(session *UserSession, food FoodItem) (*CreateFoodResponse, error)
+	SearchFood(session *UserSession, params SearchFoodRequest) ([]FoodSearchResult, error)
+	RecentFoods(session *UserSession, meal MealNumber) ([]LoggedFood, error)
+	FrequentFoods(session *UserSession) ([]LoggedFood, error)
+}
+
+// DiaryService reads and writes food diary entries
+type DiaryService interface {
+	AddFoodToDiary(session *UserSession, params FoodDiaryAddRequest) (*FoodDiaryAddResponse, error)
+	GetFoodDiary(session *UserSession, date Date) ([]FoodEntry, error)
+	GetFoodDiaryRange(session *UserSession, start, end Date) ([]FoodEntry, error)
+	DeleteDiaryEntry(session *UserSession, entryID string) error
+}
+
+var (
+	_ Authenticator = (*Client)(nil)
+	_ UserService   = (*Client)(nil)
+	_ FoodService   = (*Client)(nil)
+	_ DiaryService  = (*Client)(nil)
+)
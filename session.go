@@ -0,0 +1,151 @@
+package myfitnesspal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// sessionFormatVersion is the current version of the MarshalSession format.
+// Version 0 is a bare UserSession encoded with encoding/json, as saved before versioning.
+const sessionFormatVersion = 1
+
+// sessionCipher names the encryption used for encrypted sessions
+const sessionCipher = "AES-GCM"
+
+// ErrSessionKey is returned by UnmarshalSession when an encrypted session is read without
+// a key, or with a key other than the one it was encrypted with
+var ErrSessionKey = errors.New("wrong or missing session encryption key")
+
+// ErrSessionNotEncrypted is returned by UnmarshalSession when it is given a key but the session
+// was stored in plain text. To migrate such a session, read it with a nil key and save it again
+// with MarshalSession and the key.
+var ErrSessionNotEncrypted = errors.New("session is not encrypted")
+
+// sessionEnvelope is the versioned wrapper written by MarshalSession.
+// Exactly one of Session and Ciphertext is set.
+type sessionEnvelope struct {
+	Version    int             `json:"version"`
+	Session    json.RawMessage `json:"session,omitempty"`
+	Cipher     string          `json:"cipher,omitempty"`
+	Nonce      []byte          `json:"nonce,omitempty"`
+	Ciphertext []byte          `json:"ciphertext,omitempty"`
+}
+
+// MarshalSession encodes a session so it can be stored and later restored with UnmarshalSession.
+// If key is not nil the session is encrypted with AES-GCM, and the key must be 16, 24 or 32 bytes
+// long. Without a key the tokens are stored in plain text.
+func MarshalSession(session *UserSession, key []byte) ([]byte, error) {
+	if session == nil {
+		return nil, fmt.Errorf("no session provided")
+	}
+
+	plaintext, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding session: %w", err)
+	}
+
+	envelope := sessionEnvelope{Version: sessionFormatVersion}
+	if key == nil {
+		envelope.Session = plaintext
+		return json.Marshal(envelope)
+	}
+
+	gcm, err := newSessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	envelope.Cipher = sessionCipher
+	envelope.Nonce = nonce
+	envelope.Ciphertext = gcm.Seal(nil, nonce, plaintext, sessionAdditionalData(envelope.Version))
+	return json.Marshal(envelope)
+}
+
+// UnmarshalSession restores a session encoded by MarshalSession. With a key, the session must be
+// encrypted with it; plain text sessions are rejected with ErrSessionNotEncrypted so a tampered or
+// downgraded file can't stand in for an encrypted one. With a nil key, only plain text sessions
+// are read, including a UserSession encoded directly with encoding/json as saved before versioning.
+func UnmarshalSession(data []byte, key []byte) (*UserSession, error) {
+	var envelope sessionEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("error parsing session: %w", err)
+	}
+
+	switch envelope.Version {
+	case 0:
+		// Unversioned sessions are the UserSession itself
+		if key != nil {
+			return nil, ErrSessionNotEncrypted
+		}
+		return decodeSession(data)
+	case sessionFormatVersion:
+	default:
+		return nil, fmt.Errorf("unsupported session format version: %d", envelope.Version)
+	}
+
+	if envelope.Ciphertext == nil {
+		if envelope.Session == nil {
+			return nil, fmt.Errorf("session data is missing")
+		}
+		if key != nil {
+			return nil, ErrSessionNotEncrypted
+		}
+		return decodeSession(envelope.Session)
+	}
+
+	if envelope.Cipher != sessionCipher {
+		return nil, fmt.Errorf("unsupported session cipher: %q", envelope.Cipher)
+	}
+	if key == nil {
+		return nil, ErrSessionKey
+	}
+	gcm, err := newSessionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid session nonce length: %d", len(envelope.Nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, sessionAdditionalData(envelope.Version))
+	if err != nil {
+		// GCM can't tell a wrong key from tampered data
+		return nil, ErrSessionKey
+	}
+	return decodeSession(plaintext)
+}
+
+// decodeSession decodes a JSON encoded UserSession
+func decodeSession(data []byte) (*UserSession, error) {
+	var session UserSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("error parsing session: %w", err)
+	}
+	if session.AccessToken == "" && session.RefreshToken == "" {
+		return nil, fmt.Errorf("session has no tokens")
+	}
+	return &session, nil
+}
+
+// newSessionCipher creates the AES-GCM cipher for a session encryption key
+func newSessionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// sessionAdditionalData binds ciphertext to its format version so it can't be replayed in another
+func sessionAdditionalData(version int) []byte {
+	return []byte("myfitnesspal session v" + strconv.Itoa(version))
+}
@@ -0,0 +1,93 @@
+package myfitnesspal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var testSession = &UserSession{UserID: "12345", DomainUserID: "abcdef", AccessToken: "access", RefreshToken: "refresh"}
+
+func TestSessionRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	for _, key := range [][]byte{nil, key} {
+		data, err := MarshalSession(testSession, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != nil && bytes.Contains(data, []byte("refresh")) {
+			t.Errorf("encrypted session contains the refresh token: %s", data)
+		}
+
+		session, err := UnmarshalSession(data, key)
+		if err != nil {
+			t.Fatalf("UnmarshalSession with key %v: %v", key, err)
+		}
+		if *session != *testSession {
+			t.Errorf("UnmarshalSession = %+v, want %+v", session, testSession)
+		}
+	}
+}
+
+func TestUnmarshalSessionWrongKey(t *testing.T) {
+	data, err := MarshalSession(testSession, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range [][]byte{nil, bytes.Repeat([]byte{2}, 32)} {
+		if _, err := UnmarshalSession(data, key); !errors.Is(err, ErrSessionKey) {
+			t.Errorf("UnmarshalSession with key %v = %v, want ErrSessionKey", key, err)
+		}
+	}
+}
+
+func TestUnmarshalSessionRejectsPlaintextWithKey(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	v1, err := MarshalSession(testSession, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v0, err := json.Marshal(testSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{"v0": v0, "v1": v1} {
+		if _, err := UnmarshalSession(data, key); !errors.Is(err, ErrSessionNotEncrypted) {
+			t.Errorf("%s: UnmarshalSession with a key = %v, want ErrSessionNotEncrypted", name, err)
+		}
+	}
+}
+
+func TestUnmarshalSessionMigratesV0(t *testing.T) {
+	v0, err := json.Marshal(testSession)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := UnmarshalSession(v0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *session != *testSession {
+		t.Errorf("UnmarshalSession(v0) = %+v, want %+v", session, testSession)
+	}
+
+	// Saving it again encrypts it in the current format
+	key := bytes.Repeat([]byte{1}, 16)
+	data, err := MarshalSession(session, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope sessionEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Version != sessionFormatVersion || envelope.Ciphertext == nil {
+		t.Errorf("migrated session has version %d and ciphertext %v", envelope.Version, envelope.Ciphertext != nil)
+	}
+	if _, err := UnmarshalSession(data, key); err != nil {
+		t.Errorf("UnmarshalSession of migrated session: %v", err)
+	}
+}
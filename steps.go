@@ -0,0 +1,123 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StepsEntry represents a day's step count from one source, such as a phone or fitness tracker
+type StepsEntry struct {
+	Type   string  `json:"type"` // always "steps_aggregate"
+	Date   Date    `json:"date"`
+	Steps  int     `json:"steps"`
+	Source string  `json:"source"`           // e.g. "fitbit"
+	Energy *Energy `json:"energy,omitempty"` // Calories burned by the steps, as calculated by MyFitnessPal
+}
+
+// CalorieAdjustment represents extra calories burned reported by a synced activity tracker
+type CalorieAdjustment struct {
+	Type   string `json:"type"` // always "calorie_adjustment"
+	Date   Date   `json:"date"`
+	Source string `json:"source"`
+	Energy Energy `json:"energy"`
+}
+
+// DailyActivity represents the step counts and tracker adjustments synced for a day
+type DailyActivity struct {
+	Date        Date
+	Steps       []StepsEntry
+	Adjustments []CalorieAdjustment
+}
+
+// TotalSteps returns the day's step count. When several sources report steps,
+// the highest count is used, as the app does.
+func (a DailyActivity) TotalSteps() int {
+	var steps int
+	for _, entry := range a.Steps {
+		steps = max(steps, entry.Steps)
+	}
+	return steps
+}
+
+// AdjustmentCalories returns the extra calories burned reported by activity trackers
+func (a DailyActivity) AdjustmentCalories() float64 {
+	var calories float64
+	for _, adjustment := range a.Adjustments {
+		calories += adjustment.Energy.Calories()
+	}
+	return calories
+}
+
+// GetActivity fetches the step counts and activity tracker adjustments for a date
+func (c *Client) GetActivity(session *UserSession, date Date) (*DailyActivity, error) {
+	items, err := c.getDiaryItems(session, "GetActivity", date, "steps_aggregate", "calorie_adjustment")
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &DailyActivity{Date: date}
+	for _, item := range items {
+		var header struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(item, &header); err != nil {
+			return nil, fmt.Errorf("failed to parse activity entry: %w", err)
+		}
+
+		switch header.Type {
+		case "steps_aggregate":
+			var entry StepsEntry
+			if err := json.Unmarshal(item, &entry); err != nil {
+				return nil, fmt.Errorf("failed to parse steps entry: %w", err)
+			}
+			activity.Steps = append(activity.Steps, entry)
+		case "calorie_adjustment":
+			var adjustment CalorieAdjustment
+			if err := json.Unmarshal(item, &adjustment); err != nil {
+				return nil, fmt.Errorf("failed to parse calorie adjustment: %w", err)
+			}
+			activity.Adjustments = append(activity.Adjustments, adjustment)
+		}
+	}
+
+	return activity, nil
+}
+
+// SetSteps sets a day's step count from a source, replacing any count previously sent by it.
+// Use a source name that identifies your integration.
+func (c *Client) SetSteps(session *UserSession, date Date, steps int, source string) error {
+	if date.IsZero() {
+		return fmt.Errorf("no diary date provided")
+	}
+	if steps < 0 {
+		return fmt.Errorf("invalid step count: %d", steps)
+	}
+	if source == "" {
+		return fmt.Errorf("no steps source provided")
+	}
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"items": []StepsEntry{{
+				Type:   "steps_aggregate",
+				Date:   date,
+				Steps:  steps,
+				Source: source,
+			}},
+		})
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "SetSteps", session)
+
+	resp, err := req.Post("/v2/diary")
+	if err != nil {
+		return fmt.Errorf("failed to set steps: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return fmt.Errorf("set steps failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return nil
+}
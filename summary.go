@@ -0,0 +1,148 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DailySummary represents the calorie and macro totals shown at the top of the diary screen
+type DailySummary struct {
+	Date              Date
+	GoalCalories      float64
+	FoodCalories      float64
+	ExerciseCalories  float64 // Including activity tracker adjustments
+	NetCalories       float64 // Food minus exercise
+	RemainingCalories float64 // Goal minus net. Negative when over goal.
+
+	// Macro and micronutrient totals, with energy in calories
+	Goal      NutritionalContents
+	Consumed  NutritionalContents
+	Remaining NutritionalContents
+}
+
+// NewDailySummary computes a day's summary from its food and exercise entries and goal.
+// Calories burned through exercise are added to the day's calorie allowance, as in the app.
+func NewDailySummary(date Date, food []FoodEntry, exercise []ExerciseEntry, goal NutrientGoal) DailySummary {
+	progress := CompareToGoal(date, food, goal)
+
+	var exerciseCalories float64
+	for _, entry := range exercise {
+		exerciseCalories += entry.Energy.Calories()
+	}
+
+	summary := DailySummary{
+		Date:             date,
+		GoalCalories:     progress.Goal.Energy.Value,
+		FoodCalories:     progress.Consumed.Energy.Value,
+		ExerciseCalories: exerciseCalories,
+		Goal:             progress.Goal,
+		Consumed:         progress.Consumed,
+		Remaining:        progress.Remaining,
+	}
+	summary.updateTotals()
+	return summary
+}
+
+// AddActivity adds the calorie adjustments synced from activity trackers to the summary
+func (s *DailySummary) AddActivity(activity DailyActivity) {
+	s.ExerciseCalories += activity.AdjustmentCalories()
+	s.updateTotals()
+}
+
+// updateTotals recalculates net and remaining calories
+func (s *DailySummary) updateTotals() {
+	s.NetCalories = s.FoodCalories - s.ExerciseCalories
+	s.RemainingCalories = s.GoalCalories - s.NetCalories
+	s.Remaining.Energy.Value = s.RemainingCalories
+}
+
+// GetDailySummary fetches a day's diary, activity and goals and summarizes them
+func (c *Client) GetDailySummary(session *UserSession, date Date) (*DailySummary, error) {
+	goals, err := c.GetNutritionGoals(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	food, err := c.GetFoodDiary(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	exercise, err := c.GetExerciseDiary(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := c.GetActivity(session, date)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := NewDailySummary(date, food, exercise, goals.ForDate(date))
+	summary.AddActivity(*activity)
+	return &summary, nil
+}
+
+// DiaryCompletion represents the result of completing a diary day
+type DiaryCompletion struct {
+	Date            Date
+	Message         string // e.g. "If every day were like today... You'd weigh 160.2 lbs in 5 weeks"
+	ProjectedWeight Mass   // Projected weight in 5 weeks
+}
+
+// CompleteDiaryDay marks a day's diary as complete and returns the projected weight
+func (c *Client) CompleteDiaryDay(session *UserSession, date Date) (*DiaryCompletion, error) {
+	if date.IsZero() {
+		return nil, fmt.Errorf("no diary date provided")
+	}
+
+	req := c.apiClient.R().
+		SetBody(map[string]interface{}{
+			"date": date,
+		})
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "CompleteDiaryDay", session)
+
+	resp, err := req.Post("/v2/diary/complete")
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete diary day: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("complete diary day failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	var result struct {
+		Item struct {
+			Date            Date   `json:"date"`
+			Message         string `json:"message"`
+			ProjectedWeight struct {
+				Value float64         `json:"value"`
+				Unit  MeasurementUnit `json:"unit"`
+			} `json:"projected_weight"`
+		} `json:"item"`
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse complete diary day response: %w", err)
+	}
+
+	completion := &DiaryCompletion{
+		Date:    date,
+		Message: result.Item.Message,
+	}
+
+	projected := result.Item.ProjectedWeight
+	if projected.Unit == "" {
+		projected.Unit = Pounds
+	}
+	pounds, err := ConvertMeasurement(projected.Value, projected.Unit, Pounds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid projected weight: %w", err)
+	}
+	completion.ProjectedWeight = MassFromPounds(pounds)
+
+	return completion, nil
+}
@@ -0,0 +1,106 @@
+package myfitnesspal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// TokenStore persists user sessions. When a client has one, every session created by Login
+// or RefreshUserToken is saved to it and Logout deletes it.
+type TokenStore interface {
+	// Load returns the saved session for an identity user ID, or an error wrapping
+	// fs.ErrNotExist if there is none
+	Load(userID string) (*UserSession, error)
+	// Save saves a session, replacing any saved for the same user
+	Save(session *UserSession) error
+	// Delete removes a user's saved session. Deleting a session that doesn't exist is not an error.
+	Delete(userID string) error
+}
+
+// TokenStoreError is returned by Login and RefreshUserToken, along with the new session, when the
+// session was created but could not be saved to the token store. The session's tokens are valid,
+// and for a refresh the old refresh token no longer is, so keep the session and save it again later.
+type TokenStoreError struct {
+	Err error
+}
+
+// Error describes the failed save
+func (e *TokenStoreError) Error() string {
+	return fmt.Sprintf("error saving session to token store: %v", e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TokenStoreError) Unwrap() error {
+	return e.Err
+}
+
+// WithTokenStore saves sessions to store as they are created and deletes them on Logout
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Client) {
+		c.tokenStore = store
+	}
+}
+
+// FileTokenStore is a TokenStore keeping each session in its own file in a directory,
+// written with MarshalSession
+type FileTokenStore struct {
+	dir string
+	key []byte
+}
+
+// NewFileTokenStore creates a FileTokenStore in dir, creating the directory if needed.
+// If key is not nil sessions are encrypted with it, see MarshalSession.
+func NewFileTokenStore(dir string, key []byte) (*FileTokenStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating token store: %w", err)
+	}
+	return &FileTokenStore{dir: dir, key: key}, nil
+}
+
+// Load reads a user's session
+func (s *FileTokenStore) Load(userID string) (*UserSession, error) {
+	data, err := os.ReadFile(s.path(userID))
+	if err != nil {
+		return nil, fmt.Errorf("error reading session: %w", err)
+	}
+	return UnmarshalSession(data, s.key)
+}
+
+// Save writes a session, replacing the file atomically
+func (s *FileTokenStore) Save(session *UserSession) error {
+	if session == nil || session.UserID == "" {
+		return fmt.Errorf("session has no user ID")
+	}
+
+	data, err := MarshalSession(session, s.key)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(session.UserID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing session: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a user's session file
+func (s *FileTokenStore) Delete(userID string) error {
+	if err := os.Remove(s.path(userID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting session: %w", err)
+	}
+	return nil
+}
+
+// path returns the file a user's session is kept in
+func (s *FileTokenStore) path(userID string) string {
+	return filepath.Join(s.dir, url.PathEscape(userID)+".session")
+}
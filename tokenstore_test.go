@@ -0,0 +1,73 @@
+package myfitnesspal_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/seonixx/myfitnesspal"
+	"github.com/seonixx/myfitnesspal/mfptest"
+)
+
+// failingStore is a TokenStore whose saves fail
+type failingStore struct {
+	myfitnesspal.TokenStore
+}
+
+func (failingStore) Save(*myfitnesspal.UserSession) error {
+	return errors.New("disk full")
+}
+
+func TestFileTokenStore(t *testing.T) {
+	store, err := myfitnesspal.NewFileTokenStore(t.TempDir(), make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &myfitnesspal.UserSession{UserID: "user/1", AccessToken: "access", RefreshToken: "refresh"}
+
+	if err := store.Save(session); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.Load(session.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *session {
+		t.Errorf("Load = %+v, want %+v", loaded, session)
+	}
+
+	if err := store.Delete(session.UserID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(session.UserID); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load after Delete = %v, want fs.ErrNotExist", err)
+	}
+	if err := store.Delete(session.UserID); err != nil {
+		t.Errorf("deleting a missing session: %v", err)
+	}
+}
+
+func TestLoginReturnsSessionWhenStoreFails(t *testing.T) {
+	srv := mfptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("test@example.com", "password")
+
+	client, err := srv.NewClient(myfitnesspal.WithTokenStore(failingStore{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := client.Login("test@example.com", "password")
+	var storeErr *myfitnesspal.TokenStoreError
+	if !errors.As(err, &storeErr) {
+		t.Fatalf("Login error = %v, want a *TokenStoreError", err)
+	}
+	if session == nil || session.RefreshToken == "" {
+		t.Fatalf("Login returned session %+v with the store error, want the issued tokens", session)
+	}
+
+	// The returned tokens are live
+	if _, err := client.GetUser(session); err != nil {
+		t.Errorf("GetUser with the returned session: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package myfitnesspal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Conversion factors shared by Length, Mass and ConvertMeasurement
+const (
+	centimetersPerInch = 2.54
+	kilogramsPerPound  = 0.45359237
+	poundsPerStone     = 14
+)
+
+// Length is a distance. It is stored in inches, the unit the API uses for user profiles.
+type Length float64
+
+// LengthFromInches creates a Length from a number of inches
+func LengthFromInches(inches float64) Length {
+	return Length(inches)
+}
+
+// LengthFromCentimeters creates a Length from a number of centimeters
+func LengthFromCentimeters(cm float64) Length {
+	return Length(cm / centimetersPerInch)
+}
+
+// Inches returns the length in inches
+func (l Length) Inches() float64 {
+	return float64(l)
+}
+
+// Centimeters returns the length in centimeters
+func (l Length) Centimeters() float64 {
+	return float64(l) * centimetersPerInch
+}
+
+// FeetAndInches splits the length into whole feet and the remaining inches
+func (l Length) FeetAndInches() (feet int, inches float64) {
+	feet = int(math.Floor(float64(l) / 12))
+	return feet, float64(l) - float64(feet*12)
+}
+
+// Format formats the length as a height in the units usual for the locale, e.g. 5' 10" or 178 cm
+func (l Length) Format(locale string) string {
+	if usesImperialHeight(locale) {
+		// Round before splitting so 71.6 inches is 6' 0" rather than 5' 12"
+		inches := int(math.Round(float64(l)))
+		return fmt.Sprintf("%d' %d\"", inches/12, inches%12)
+	}
+	return fmt.Sprintf("%.0f cm", l.Centimeters())
+}
+
+// Mass is a weight. It is stored in pounds, the unit the API uses for user profiles.
+type Mass float64
+
+// MassFromPounds creates a Mass from a number of pounds
+func MassFromPounds(pounds float64) Mass {
+	return Mass(pounds)
+}
+
+// MassFromKilograms creates a Mass from a number of kilograms
+func MassFromKilograms(kg float64) Mass {
+	return Mass(kg / kilogramsPerPound)
+}
+
+// Pounds returns the mass in pounds
+func (m Mass) Pounds() float64 {
+	return float64(m)
+}
+
+// Kilograms returns the mass in kilograms
+func (m Mass) Kilograms() float64 {
+	return float64(m) * kilogramsPerPound
+}
+
+// StonesAndPounds splits the mass into whole stones and the remaining pounds
+func (m Mass) StonesAndPounds() (stones int, pounds float64) {
+	stones = int(math.Floor(float64(m) / poundsPerStone))
+	return stones, float64(m) - float64(stones*poundsPerStone)
+}
+
+// Format formats the mass in the units usual for the locale, e.g. 165.0 lb, 11 st 11 lb or 74.8 kg
+func (m Mass) Format(locale string) string {
+	switch localeCountry(locale) {
+	case "US", "LR", "MM":
+		return fmt.Sprintf("%.1f lb", m.Pounds())
+	case "GB", "IE":
+		// Round before splitting so 167.8 lb is 12 st 0 lb rather than 11 st 14 lb
+		pounds := int(math.Round(m.Pounds()))
+		return fmt.Sprintf("%d st %d lb", pounds/poundsPerStone, pounds%poundsPerStone)
+	default:
+		return fmt.Sprintf("%.1f kg", m.Kilograms())
+	}
+}
+
+// usesImperialHeight reports whether heights are usually given in feet and inches in the locale
+func usesImperialHeight(locale string) bool {
+	switch localeCountry(locale) {
+	case "US", "LR", "MM", "GB", "IE":
+		return true
+	}
+	return false
+}
+
+// localeCountry returns the upper case country part of a locale such as "en_US" or "en-GB"
+func localeCountry(locale string) string {
+	if i := strings.IndexAny(locale, "_-"); i >= 0 {
+		return strings.ToUpper(locale[i+1:])
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package myfitnesspal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLengthFormat(t *testing.T) {
+	tests := []struct {
+		inches float64
+		locale string
+		want   string
+	}{
+		{70, "en_US", `5' 10"`},
+		{71.6, "en_US", `6' 0"`},
+		{71.4, "en_GB", `5' 11"`},
+		{70, "fr_FR", "178 cm"},
+	}
+	for _, tt := range tests {
+		if got := LengthFromInches(tt.inches).Format(tt.locale); got != tt.want {
+			t.Errorf("LengthFromInches(%v).Format(%q) = %q, want %q", tt.inches, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestMassFormat(t *testing.T) {
+	tests := []struct {
+		pounds float64
+		locale string
+		want   string
+	}{
+		{165, "en_US", "165.0 lb"},
+		{165, "en_GB", "11 st 11 lb"},
+		{167.8, "en_GB", "12 st 0 lb"},
+		{165, "de_DE", "74.8 kg"},
+	}
+	for _, tt := range tests {
+		if got := MassFromPounds(tt.pounds).Format(tt.locale); got != tt.want {
+			t.Errorf("MassFromPounds(%v).Format(%q) = %q, want %q", tt.pounds, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestUnitsMatchConvertMeasurement(t *testing.T) {
+	kg, err := ConvertMeasurement(165, Pounds, Kilograms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MassFromPounds(165).Kilograms(); math.Abs(got-kg) > 1e-9 {
+		t.Errorf("Mass.Kilograms() = %v, ConvertMeasurement = %v", got, kg)
+	}
+
+	cm, err := ConvertMeasurement(70, Inches, Centimeters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := LengthFromInches(70).Centimeters(); math.Abs(got-cm) > 1e-9 {
+		t.Errorf("Length.Centimeters() = %v, ConvertMeasurement = %v", got, cm)
+	}
+}
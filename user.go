@@ -2,6 +2,7 @@ package myfitnesspal
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -48,18 +49,83 @@ type UserProfile struct {
 	ProfilePictureURI string `json:"profilePictureUri"`
 	Birthdate        string  `json:"birthdate"`
 	Gender           string  `json:"gender"`
-	Weight           float64 `json:"weight"`
-	Height           float64 `json:"height"` // Height in inches
+	Weight           Mass    `json:"weight"`
+	Height           Length  `json:"height"`
 	Locale           string  `json:"locale"`
-	Location         struct {
-		PostalCode string `json:"postalCode"`
-		Country    string `json:"country"`
-	} `json:"location"`
+	Location         ProfileLocation `json:"location"`
+}
+
+// ProfileLocation represents where a user lives
+type ProfileLocation struct {
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+// ProfilePatch represents a partial update to a user's profile. Only set fields are changed.
+type ProfilePatch struct {
+	DisplayName *string          `json:"displayName,omitempty"`
+	Height      *Length          `json:"height,omitempty"`
+	Weight      *Mass            `json:"weight,omitempty"`
+	Birthdate   *Date            `json:"birthdate,omitempty"`
+	Gender      *string          `json:"gender,omitempty"`
+	Locale      *string          `json:"locale,omitempty"`
+	Location    *ProfileLocation `json:"location,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p ProfilePatch) IsEmpty() bool {
+	return p == ProfilePatch{}
 }
 
 // HeightInCM returns the height in centimeters
 func (p *UserProfile) HeightInCM() float64 {
-	return p.Height * 2.54 // Convert inches to centimeters
+	return p.Height.Centimeters()
+}
+
+// FormatHeight formats the user's height in the units usual for their locale
+func (p *UserProfile) FormatHeight() string {
+	return p.Height.Format(p.Locale)
+}
+
+// FormatWeight formats the user's weight in the units usual for their locale
+func (p *UserProfile) FormatWeight() string {
+	return p.Weight.Format(p.Locale)
+}
+
+// BirthdateTime parses the user's birthdate
+func (p *UserProfile) BirthdateTime() (time.Time, error) {
+	if p.Birthdate == "" {
+		return time.Time{}, fmt.Errorf("no birthdate in profile")
+	}
+	if t, err := time.Parse(dateLayout, p.Birthdate); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, p.Birthdate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid birthdate %q: %w", p.Birthdate, err)
+	}
+	return t, nil
+}
+
+// Age returns the user's age in whole years today, in the user's timezone if their profile
+// gives one and in local time otherwise
+func (p *UserProfile) Age() (int, error) {
+	birthdate, err := p.BirthdateTime()
+	if err != nil {
+		return 0, err
+	}
+
+	born := DateOf(birthdate)
+	loc, err := p.TimeZone()
+	if err != nil {
+		loc = time.Local
+	}
+	today := Today(loc)
+	age := today.Year - born.Year
+	if today.Month < born.Month || (today.Month == born.Month && today.Day < born.Day) {
+		age--
+	}
+	return age, nil
 }
 
 // GetUser fetches the user's information from the API
@@ -71,7 +137,7 @@ func (c *Client) GetUser(session *UserSession) (*User, error) {
 		SetResult(&user)
 
 	// Set standard headers first
-	c.setStandardHeaders(req, session)
+	c.setStandardHeaders(req, "GetUser", session)
 
 	resp, err := req.Get("/users/" + session.UserID + "?fetch_profile=true&fetch_emails=true")
 	if err != nil {
@@ -83,4 +149,30 @@ func (c *Client) GetUser(session *UserSession) (*User, error) {
 	}
 
 	return &user, nil
-}
\ No newline at end of file
+}
+
+// UpdateProfile changes the fields set in patch on the user's profile and returns the updated user
+func (c *Client) UpdateProfile(session *UserSession, patch ProfilePatch) (*User, error) {
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("no profile fields to update")
+	}
+
+	req := c.identityClient.R().
+		SetBody(map[string]interface{}{
+			"profile": patch,
+		})
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "UpdateProfile", session)
+
+	resp, err := req.Patch("/users/" + session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusNoContent {
+		return nil, fmt.Errorf("update profile failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return c.GetUser(session)
+}
@@ -0,0 +1,150 @@
+package myfitnesspal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WaterUnit is a unit of volume for water consumption
+type WaterUnit string
+
+const (
+	Milliliters WaterUnit = "milliliters"
+	FluidOunces WaterUnit = "fl_oz" // US fluid ounces
+	Cups        WaterUnit = "cups"  // US cups of 8 fluid ounces
+)
+
+// millilitersPer is the number of milliliters in one of each unit
+var millilitersPer = map[WaterUnit]float64{
+	Milliliters: 1,
+	FluidOunces: 29.5735295625,
+	Cups:        236.5882365,
+}
+
+// ConvertWater converts a volume of water from one unit to another
+func ConvertWater(value float64, from, to WaterUnit) (float64, error) {
+	fromML, ok := millilitersPer[from]
+	if !ok {
+		return 0, fmt.Errorf("invalid water unit: %s", from)
+	}
+	toML, ok := millilitersPer[to]
+	if !ok {
+		return 0, fmt.Errorf("invalid water unit: %s", to)
+	}
+	return value * fromML / toML, nil
+}
+
+// WaterEntry represents the water a user drank on a given day
+type WaterEntry struct {
+	Date        Date    `json:"date"`
+	Milliliters float64 `json:"milliliters"`
+}
+
+// In returns the amount of water drunk in the given unit
+func (w WaterEntry) In(unit WaterUnit) (float64, error) {
+	return ConvertWater(w.Milliliters, Milliliters, unit)
+}
+
+// waterItem is a water entry as the diary API represents it
+type waterItem struct {
+	Type  string    `json:"type"` // always "water_entry"
+	Date  Date      `json:"date"`
+	Value float64   `json:"value"`
+	Unit  WaterUnit `json:"unit"`
+}
+
+// GetWater fetches the water a user drank on a date
+func (c *Client) GetWater(session *UserSession, date Date) (*WaterEntry, error) {
+	items, err := c.getDiaryItems(session, "GetWater", date, "water_entry")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &WaterEntry{Date: date}
+	for _, raw := range items {
+		var item waterItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("failed to parse water entry: %w", err)
+		}
+		if item.Unit == "" {
+			item.Unit = Milliliters
+		}
+		ml, err := ConvertWater(item.Value, item.Unit, Milliliters)
+		if err != nil {
+			return nil, err
+		}
+		entry.Milliliters += ml
+	}
+
+	return entry, nil
+}
+
+// GetWaterRange fetches the water a user drank on each day from start to end inclusive
+func (c *Client) GetWaterRange(session *UserSession, start, end Date) ([]WaterEntry, error) {
+	var entries []WaterEntry
+	for date := range DateRange(start, end) {
+		entry, err := c.GetWater(session, date)
+		if err != nil {
+			return nil, fmt.Errorf("error getting water for %s: %w", date, err)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// SetWater sets the total amount of water a user drank on a date, replacing any previous amount
+func (c *Client) SetWater(session *UserSession, date Date, amount float64, unit WaterUnit) (*WaterEntry, error) {
+	if date.IsZero() {
+		return nil, fmt.Errorf("no diary date provided")
+	}
+	if amount < 0 {
+		return nil, fmt.Errorf("invalid water amount: %v", amount)
+	}
+
+	ml, err := ConvertWater(amount, unit, Milliliters)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"items": []waterItem{{
+			Type:  "water_entry",
+			Date:  date,
+			Value: ml,
+			Unit:  Milliliters,
+		}},
+	}
+
+	req := c.apiClient.R().
+		SetBody(body)
+
+	// Set standard headers first
+	c.setStandardHeaders(req, "SetWater", session)
+
+	resp, err := req.Post("/v2/diary")
+	if err != nil {
+		return nil, fmt.Errorf("failed to set water: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return nil, fmt.Errorf("set water failed with status %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return &WaterEntry{Date: date, Milliliters: ml}, nil
+}
+
+// AddWater adds to the amount of water a user drank on a date and returns the new total
+func (c *Client) AddWater(session *UserSession, date Date, amount float64, unit WaterUnit) (*WaterEntry, error) {
+	current, err := c.GetWater(session, date)
+	if err != nil {
+		return nil, fmt.Errorf("error getting current water: %w", err)
+	}
+
+	added, err := ConvertWater(amount, unit, Milliliters)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SetWater(session, date, current.Milliliters+added, Milliliters)
+}
@@ -0,0 +1,22 @@
+package myfitnesspal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWaterEntryIn(t *testing.T) {
+	entry := WaterEntry{Milliliters: 473.176473}
+
+	cups, err := entry.In(Cups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cups-2) > 1e-6 {
+		t.Errorf("In(Cups) = %v, want 2", cups)
+	}
+
+	if _, err := entry.In("gallons"); err == nil {
+		t.Error("In with an unknown unit succeeded")
+	}
+}